@@ -47,6 +47,8 @@ func main() {
 	flag.DurationVar(&s.restCfg.LeaderConfig.Duration, "duration", time.Second*5, "the lease lock resource name")
 	flag.DurationVar(&s.restCfg.AddonCacheTime, "addon-cache-duration", time.Minute*10, "how long between two addon cache operation")
 	flag.BoolVar(&s.restCfg.DisableStatisticCronJob, "disable-statistic-cronJob", false, "close the system statistic info calculating cronJob")
+	flag.BoolVar(&s.restCfg.DisableCache, "disable-cache", false, "disable the in-memory cache of the system info, definitions and addon catalogs. "+
+		"The cache is invalidated by the writes of the same replica, the writes of the other replicas are only seen after the cached data expires")
 	flag.Parse()
 
 	if len(os.Args) > 2 && os.Args[1] == "build-swagger" {
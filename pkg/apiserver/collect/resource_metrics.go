@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collect
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/log"
+	"github.com/oam-dev/kubevela/pkg/apiserver/metrics"
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+)
+
+// ResourceMetricsInterval is the interval of updating the application and cluster number gauges
+var ResourceMetricsInterval = time.Minute
+
+// StartResourceMetrics updates the application and cluster number gauges at startup and then periodically
+// until the context is done. It is independent of the statistic job, so the gauges work without the telemetry.
+func StartResourceMetrics(ctx context.Context, ds datastore.DataStore) {
+	i := InfoCalculateCronJob{
+		ds: ds,
+	}
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := i.updateResourceGauges(ctx); err != nil {
+			log.Logger.Errorf("failed to update the resource metrics: %s", err.Error())
+		}
+	}, ResourceMetricsInterval)
+}
+
+func (i InfoCalculateCronJob) updateResourceGauges(ctx context.Context) error {
+	appCount, err := i.ds.Count(ctx, &model.Application{}, nil)
+	if err != nil {
+		return err
+	}
+	clusterCount, err := i.calculateClusterInfo(ctx)
+	if err != nil {
+		return err
+	}
+	metrics.ApplicationNumberGauge.Set(float64(appCount))
+	metrics.ClusterNumberGauge.Set(float64(clusterCount))
+	return nil
+}
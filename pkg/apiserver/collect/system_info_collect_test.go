@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/onsi/gomega/format"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"gotest.tools/assert"
 
@@ -35,6 +36,7 @@ import (
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/metrics"
 	"github.com/oam-dev/kubevela/pkg/apiserver/model"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
@@ -140,6 +142,12 @@ var _ = Describe("Test calculate cronJob", func() {
 		}))
 	})
 
+	It("Test update the resource gauges", func() {
+		Expect(i.updateResourceGauges(ctx)).Should(BeNil())
+		Expect(testutil.ToFloat64(metrics.ApplicationNumberGauge)).Should(BeNumerically(">=", 2))
+		Expect(testutil.ToFloat64(metrics.ClusterNumberGauge)).Should(BeEquivalentTo(1))
+	})
+
 	It("Test run func", func() {
 		app3 := model.Application{BaseModel: model.BaseModel{CreateTime: time.Now()}, Name: "app3", Project: testProject}
 		Expect(ds.Add(ctx, &app3)).Should(BeNil())
@@ -106,7 +106,7 @@ type SortOption struct {
 	Order SortOrder
 }
 
-// FuzzyQueryOption defines the fuzzy query search filter option
+// FuzzyQueryOption defines the fuzzy query search filter option, the query is matched literally by all drivers
 type FuzzyQueryOption struct {
 	Key   string
 	Query string
@@ -123,11 +123,28 @@ type IsNotExistQueryOption struct {
 	Key string
 }
 
+// EqualQueryOption defines the exact match filter option, the key could be any field of the entity.
+// The value is compared with the field by type, it must be a string, a bool or a number (int, int64 or float64).
+// A string only matches a string field, a bool only matches a boolean field and a number only matches a numeric field.
+type EqualQueryOption struct {
+	Key   string
+	Value interface{}
+}
+
+// KeywordQueryOption defines the case-insensitive search filter option,
+// it matches the records whose value of any key contains the keyword.
+type KeywordQueryOption struct {
+	Keys    []string
+	Keyword string
+}
+
 // FilterOptions filter query returned items
 type FilterOptions struct {
 	Queries    []FuzzyQueryOption
 	In         []InQueryOption
 	IsNotExist []IsNotExistQueryOption
+	Equals     []EqualQueryOption
+	Keywords   []KeywordQueryOption
 }
 
 // ListOptions list api options
@@ -253,6 +253,12 @@ func newBySortOptionConfigMap(items []corev1.ConfigMap, sortBy []datastore.SortO
 			switch res.Type {
 			case gjson.Number:
 				m[op.Key] = res.Num
+			case gjson.String:
+				if t := res.Time(); !t.IsZero() {
+					m[op.Key] = t
+				} else {
+					m[op.Key] = res.Str
+				}
 			default:
 				if !res.Time().IsZero() {
 					m[op.Key] = res.Time()
@@ -279,6 +285,17 @@ func (b bySortOptionConfigMap) Less(i, j int) bool {
 	for _, op := range b.sortBy {
 		x := b.objects[i][op.Key]
 		y := b.objects[j][op.Key]
+		xStr, xok := x.(string)
+		yStr, yok := y.(string)
+		if xok && yok {
+			if xStr == yStr {
+				continue
+			}
+			if op.Order == datastore.SortOrderAscending {
+				return xStr < yStr
+			}
+			return xStr > yStr
+		}
 		_x, xok := x.(time.Time)
 		_y, yok := y.(time.Time)
 		var xScore, yScore float64
@@ -311,23 +328,65 @@ func _sortConfigMapBySortOptions(items []corev1.ConfigMap, sortOptions []datasto
 	return so.items
 }
 
-func _filterConfigMapByFuzzyQueryOptions(items []corev1.ConfigMap, queries []datastore.FuzzyQueryOption) []corev1.ConfigMap {
+func _filterConfigMapByFilterOptions(items []corev1.ConfigMap, filterOptions datastore.FilterOptions) []corev1.ConfigMap {
 	var _items []corev1.ConfigMap
 	for _, item := range items {
-		data := string(item.BinaryData["data"])
-		valid := true
-		for _, query := range queries {
-			res := gjson.Get(data, query.Key)
-			if res.Type != gjson.String || !strings.Contains(res.Str, query.Query) {
-				valid = false
+		if _matchFilterOptions(string(item.BinaryData["data"]), filterOptions) {
+			_items = append(_items, item)
+		}
+	}
+	return _items
+}
+
+func _matchFilterOptions(data string, filterOptions datastore.FilterOptions) bool {
+	for _, query := range filterOptions.Queries {
+		res := gjson.Get(data, query.Key)
+		if res.Type != gjson.String || !strings.Contains(res.Str, query.Query) {
+			return false
+		}
+	}
+	for _, query := range filterOptions.Equals {
+		if !_matchEqualValue(gjson.Get(data, query.Key), query.Value) {
+			return false
+		}
+	}
+	for _, query := range filterOptions.Keywords {
+		keyword := strings.ToLower(query.Keyword)
+		matched := false
+		for _, key := range query.Keys {
+			res := gjson.Get(data, key)
+			if res.Type == gjson.String && strings.Contains(strings.ToLower(res.Str), keyword) {
+				matched = true
 				break
 			}
 		}
-		if valid {
-			_items = append(_items, item)
+		if !matched {
+			return false
 		}
 	}
-	return _items
+	return true
+}
+
+// _matchEqualValue compares the value with the field by type, the value of other types never matches
+func _matchEqualValue(res gjson.Result, value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return res.Type == gjson.String && res.Str == v
+	case bool:
+		return (res.Type == gjson.True || res.Type == gjson.False) && res.Bool() == v
+	case int:
+		return res.Type == gjson.Number && res.Num == float64(v)
+	case int64:
+		return res.Type == gjson.Number && res.Num == float64(v)
+	case float64:
+		return res.Type == gjson.Number && res.Num == v
+	default:
+		return false
+	}
+}
+
+func _needFilterConfigMap(filterOptions datastore.FilterOptions) bool {
+	return len(filterOptions.Queries) > 0 || len(filterOptions.Equals) > 0 || len(filterOptions.Keywords) > 0
 }
 
 // List will list all database records by select labels according to table name
@@ -389,8 +448,8 @@ func (m *kubeapi) List(ctx context.Context, entity datastore.Entity, op *datasto
 		return nil, datastore.NewDBError(err)
 	}
 	items := configMaps.Items
-	if op != nil && len(op.Queries) > 0 {
-		items = _filterConfigMapByFuzzyQueryOptions(items, op.Queries)
+	if op != nil && _needFilterConfigMap(op.FilterOptions) {
+		items = _filterConfigMapByFilterOptions(items, op.FilterOptions)
 	}
 	if op != nil && len(op.SortBy) > 0 {
 		items = _sortConfigMapBySortOptions(items, op.SortBy)
@@ -468,8 +527,8 @@ func (m *kubeapi) Count(ctx context.Context, entity datastore.Entity, filterOpti
 		return 0, datastore.NewDBError(err)
 	}
 	items := configMaps.Items
-	if filterOptions != nil && len(filterOptions.Queries) > 0 {
-		items = _filterConfigMapByFuzzyQueryOptions(configMaps.Items, filterOptions.Queries)
+	if filterOptions != nil && _needFilterConfigMap(*filterOptions) {
+		items = _filterConfigMapByFilterOptions(items, *filterOptions)
 	}
 	return int64(len(items)), nil
 }
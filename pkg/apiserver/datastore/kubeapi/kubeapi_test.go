@@ -230,6 +230,114 @@ var _ = Describe("Test kubeapi datastore driver", func() {
 		}
 	})
 
+	It("Test list clusters with equal and keyword filters", func() {
+		clusters := []*model.Cluster{
+			{Name: "alpha", Alias: "Production East", Status: "Healthy"},
+			{Name: "beta", Alias: "Staging", Status: "Healthy"},
+			{Name: "gamma", Alias: "production-west", Status: "Healthy"},
+			{Name: "delta", Alias: "production-north", Status: "Unhealthy"},
+		}
+		for _, cluster := range clusters {
+			Expect(kubeStore.Add(context.TODO(), cluster)).Should(Succeed())
+		}
+		filterOptions := datastore.FilterOptions{
+			Equals:   []datastore.EqualQueryOption{{Key: "status", Value: "Healthy"}},
+			Keywords: []datastore.KeywordQueryOption{{Keys: []string{"name", "alias"}, Keyword: "PRODUCTION"}},
+		}
+		entities, err := kubeStore.List(context.TODO(), &model.Cluster{}, &datastore.ListOptions{
+			SortBy:        []datastore.SortOption{{Key: "name", Order: datastore.SortOrderDescending}},
+			FilterOptions: filterOptions,
+		})
+		Expect(err).Should(Succeed())
+		Expect(len(entities)).Should(Equal(2))
+		for i, name := range []string{"gamma", "alpha"} {
+			Expect(entities[i].(*model.Cluster).Name).Should(Equal(name))
+		}
+		count, err := kubeStore.Count(context.TODO(), &model.Cluster{}, &filterOptions)
+		Expect(err).Should(Succeed())
+		Expect(count).Should(Equal(int64(2)))
+		for _, cluster := range clusters {
+			Expect(kubeStore.Delete(context.TODO(), cluster)).Should(Succeed())
+		}
+	})
+
+	It("Test the fuzzy query and the keyword are matched literally", func() {
+		clusters := []*model.Cluster{
+			{Name: "literal-a", Alias: "release v1.2 (prod)"},
+			{Name: "literal-b", Alias: "release v102"},
+			{Name: "literal-c", Alias: "release+prod"},
+		}
+		for _, cluster := range clusters {
+			Expect(kubeStore.Add(context.TODO(), cluster)).Should(Succeed())
+		}
+		// the regex metacharacters in the query are not interpreted
+		for query, names := range map[string][]string{
+			"v1.2":   {"literal-a"},
+			"(prod)": {"literal-a"},
+			"e+p":    {"literal-c"},
+			".*":     {},
+		} {
+			entities, err := kubeStore.List(context.TODO(), &model.Cluster{}, &datastore.ListOptions{
+				FilterOptions: datastore.FilterOptions{Queries: []datastore.FuzzyQueryOption{{Key: "alias", Query: query}}},
+			})
+			Expect(err).Should(Succeed())
+			var matched []string
+			for _, entity := range entities {
+				matched = append(matched, entity.(*model.Cluster).Name)
+			}
+			Expect(matched).Should(ConsistOf(names), query)
+		}
+		entities, err := kubeStore.List(context.TODO(), &model.Cluster{}, &datastore.ListOptions{
+			FilterOptions: datastore.FilterOptions{Keywords: []datastore.KeywordQueryOption{{Keys: []string{"name", "alias"}, Keyword: "V1.2 (PROD)"}}},
+		})
+		Expect(err).Should(Succeed())
+		Expect(len(entities)).Should(Equal(1))
+		Expect(entities[0].(*model.Cluster).Name).Should(Equal("literal-a"))
+		for _, cluster := range clusters {
+			Expect(kubeStore.Delete(context.TODO(), cluster)).Should(Succeed())
+		}
+	})
+
+	It("Test the equal filters are compared by type", func() {
+		users := []*model.User{
+			{Name: "typed-alice", Email: "alice@test.com", Disabled: true},
+			{Name: "typed-bob", Email: "bob@test.com"},
+		}
+		for _, user := range users {
+			Expect(kubeStore.Add(context.TODO(), user)).Should(Succeed())
+		}
+		for _, c := range []struct {
+			filter datastore.EqualQueryOption
+			names  []string
+		}{
+			{filter: datastore.EqualQueryOption{Key: "disabled", Value: true}, names: []string{"typed-alice"}},
+			{filter: datastore.EqualQueryOption{Key: "disabled", Value: false}, names: []string{"typed-bob"}},
+			{filter: datastore.EqualQueryOption{Key: "email", Value: "bob@test.com"}, names: []string{"typed-bob"}},
+			// a string never matches a boolean field
+			{filter: datastore.EqualQueryOption{Key: "disabled", Value: "true"}},
+			// a number never matches a string field
+			{filter: datastore.EqualQueryOption{Key: "name", Value: 1}},
+		} {
+			filterOptions := datastore.FilterOptions{
+				Equals:   []datastore.EqualQueryOption{c.filter},
+				Keywords: []datastore.KeywordQueryOption{{Keys: []string{"name"}, Keyword: "typed-"}},
+			}
+			entities, err := kubeStore.List(context.TODO(), &model.User{}, &datastore.ListOptions{FilterOptions: filterOptions})
+			Expect(err).Should(Succeed())
+			var names []string
+			for _, entity := range entities {
+				names = append(names, entity.(*model.User).Name)
+			}
+			Expect(names).Should(ConsistOf(c.names))
+			count, err := kubeStore.Count(context.TODO(), &model.User{}, &filterOptions)
+			Expect(err).Should(Succeed())
+			Expect(count).Should(Equal(int64(len(c.names))))
+		}
+		for _, user := range users {
+			Expect(kubeStore.Delete(context.TODO(), user)).Should(Succeed())
+		}
+	})
+
 	It("Test count function", func() {
 		var app model.Application
 		count, err := kubeStore.Count(context.TODO(), &app, nil)
@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/metrics"
+)
+
+// metricsDataStore records the latency and errors of the operations of the wrapped datastore
+type metricsDataStore struct {
+	ds DataStore
+}
+
+// WithMetrics wraps the datastore to record the operation metrics
+func WithMetrics(ds DataStore) DataStore {
+	return &metricsDataStore{ds: ds}
+}
+
+func (m *metricsDataStore) observe(operation, table string, start time.Time, err error) {
+	metrics.DatastoreOperationDurationHistogram.WithLabelValues(operation, table).Observe(time.Since(start).Seconds())
+	if err != nil && !errors.Is(err, ErrRecordExist) && !errors.Is(err, ErrRecordNotExist) {
+		metrics.DatastoreOperationErrorCounter.WithLabelValues(operation, table).Inc()
+	}
+}
+
+func tableName(entity Entity) string {
+	if entity == nil {
+		return ""
+	}
+	return entity.TableName()
+}
+
+func (m *metricsDataStore) Add(ctx context.Context, entity Entity) (err error) {
+	defer func(start time.Time) { m.observe("add", tableName(entity), start, err) }(time.Now())
+	return m.ds.Add(ctx, entity)
+}
+
+func (m *metricsDataStore) BatchAdd(ctx context.Context, entities []Entity) (err error) {
+	var table string
+	if len(entities) > 0 {
+		table = tableName(entities[0])
+	}
+	defer func(start time.Time) { m.observe("batch_add", table, start, err) }(time.Now())
+	return m.ds.BatchAdd(ctx, entities)
+}
+
+func (m *metricsDataStore) Put(ctx context.Context, entity Entity) (err error) {
+	defer func(start time.Time) { m.observe("put", tableName(entity), start, err) }(time.Now())
+	return m.ds.Put(ctx, entity)
+}
+
+func (m *metricsDataStore) Delete(ctx context.Context, entity Entity) (err error) {
+	defer func(start time.Time) { m.observe("delete", tableName(entity), start, err) }(time.Now())
+	return m.ds.Delete(ctx, entity)
+}
+
+func (m *metricsDataStore) Get(ctx context.Context, entity Entity) (err error) {
+	defer func(start time.Time) { m.observe("get", tableName(entity), start, err) }(time.Now())
+	return m.ds.Get(ctx, entity)
+}
+
+func (m *metricsDataStore) List(ctx context.Context, query Entity, options *ListOptions) (list []Entity, err error) {
+	defer func(start time.Time) { m.observe("list", tableName(query), start, err) }(time.Now())
+	return m.ds.List(ctx, query, options)
+}
+
+func (m *metricsDataStore) Count(ctx context.Context, entity Entity, options *FilterOptions) (count int64, err error) {
+	defer func(start time.Time) { m.observe("count", tableName(entity), start, err) }(time.Now())
+	return m.ds.Count(ctx, entity, options)
+}
+
+func (m *metricsDataStore) IsExist(ctx context.Context, entity Entity) (exist bool, err error) {
+	defer func(start time.Time) { m.observe("is_exist", tableName(entity), start, err) }(time.Now())
+	return m.ds.IsExist(ctx, entity)
+}
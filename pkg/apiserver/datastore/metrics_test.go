@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/metrics"
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+)
+
+type fakeDataStore struct {
+	DataStore
+	err error
+}
+
+func (f *fakeDataStore) Get(ctx context.Context, entity Entity) error {
+	return f.err
+}
+
+var _ = Describe("Test the datastore with metrics", func() {
+
+	It("Test record the operation errors", func() {
+		fake := &fakeDataStore{}
+		ds := WithMetrics(fake)
+		table := (&model.Project{}).TableName()
+		counter := metrics.DatastoreOperationErrorCounter.WithLabelValues("get", table)
+		before := testutil.ToFloat64(counter)
+
+		Expect(ds.Get(context.TODO(), &model.Project{Name: "p"})).Should(BeNil())
+		Expect(testutil.ToFloat64(counter)).Should(Equal(before))
+
+		fake.err = ErrRecordNotExist
+		Expect(ds.Get(context.TODO(), &model.Project{Name: "p"})).Should(Equal(ErrRecordNotExist))
+		Expect(testutil.ToFloat64(counter)).Should(Equal(before))
+
+		fake.err = errors.New("connection refused")
+		Expect(ds.Get(context.TODO(), &model.Project{Name: "p"})).ShouldNot(BeNil())
+		Expect(testutil.ToFloat64(counter)).Should(Equal(before + 1))
+	})
+})
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
 	"cuelang.org/go/pkg/strings"
@@ -193,7 +194,22 @@ func (m *mongodb) Delete(ctx context.Context, entity datastore.Entity) error {
 
 func _applyFilterOptions(filter bson.D, filterOptions datastore.FilterOptions) bson.D {
 	for _, queryOp := range filterOptions.Queries {
-		filter = append(filter, bson.E{Key: strings.ToLower(queryOp.Key), Value: bsonx.Regex(".*"+queryOp.Query+".*", "s")})
+		filter = append(filter, bson.E{Key: strings.ToLower(queryOp.Key), Value: bsonx.Regex(".*"+regexp.QuoteMeta(queryOp.Query)+".*", "s")})
+	}
+	for _, queryOp := range filterOptions.Equals {
+		// the value is compared by type by the mongodb, a string never matches a boolean or numeric field
+		filter = append(filter, bson.E{Key: strings.ToLower(queryOp.Key), Value: queryOp.Value})
+	}
+	if len(filterOptions.Keywords) > 0 {
+		var conditions bson.A
+		for _, queryOp := range filterOptions.Keywords {
+			var or bson.A
+			for _, key := range queryOp.Keys {
+				or = append(or, bson.D{bson.E{Key: strings.ToLower(key), Value: bsonx.Regex(regexp.QuoteMeta(queryOp.Keyword), "i")}})
+			}
+			conditions = append(conditions, bson.D{bson.E{Key: "$or", Value: or}})
+		}
+		filter = append(filter, bson.E{Key: "$and", Value: conditions})
 	}
 	for _, queryOp := range filterOptions.In {
 		filter = append(filter, bson.E{Key: strings.ToLower(queryOp.Key), Value: bson.D{bson.E{Key: "$in", Value: queryOp.Values}}})
@@ -275,7 +291,7 @@ func (m *mongodb) Count(ctx context.Context, entity datastore.Entity, filterOpti
 	if entity.Index() != nil {
 		for k, v := range entity.Index() {
 			filter = append(filter, bson.E{
-				Key:   k,
+				Key:   strings.ToLower(k),
 				Value: v,
 			})
 		}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "kubevela_apiserver"
+
+var histogramBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+var (
+	// RequestCounter report the request number of every webservice.
+	RequestCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "request_total",
+		Help:      "request number of every webservice.",
+	}, []string{"webservice", "method", "code"})
+
+	// RequestDurationHistogram report the request duration of every webservice.
+	RequestDurationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "request duration distributions of every webservice.",
+		Buckets:   histogramBuckets,
+	}, []string{"webservice", "method"})
+)
+
+var (
+	// DatastoreOperationDurationHistogram report the datastore operation duration.
+	DatastoreOperationDurationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "datastore_operation_duration_seconds",
+		Help:      "datastore operation duration distributions.",
+		Buckets:   histogramBuckets,
+	}, []string{"operation", "table"})
+
+	// DatastoreOperationErrorCounter report the failed datastore operation number.
+	DatastoreOperationErrorCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "datastore_operation_errors_total",
+		Help:      "failed datastore operation number, the record exist and not exist errors are not counted.",
+	}, []string{"operation", "table"})
+)
+
+var (
+	// LoginCounter report the login number of every login type.
+	LoginCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_total",
+		Help:      "login number of every login type.",
+	}, []string{"login_type", "result"})
+)
+
+var (
+	// ApplicationNumberGauge report the number of applications, it is updated periodically by every apiserver replica.
+	ApplicationNumberGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "application_number",
+		Help:      "application number managed by the apiserver.",
+	})
+
+	// ClusterNumberGauge report the number of clusters, it is updated periodically by every apiserver replica.
+	ClusterNumberGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cluster_number",
+		Help:      "cluster number managed by the apiserver.",
+	})
+)
+
+var (
+	// CacheRequestCounter report the hit and miss number of the usecase cache.
+	CacheRequestCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "request number of the usecase cache, the result is hit or miss.",
+	}, []string{"cache", "result"})
+)
+
+const (
+	// CacheResultHit is the result label of the cache hit
+	CacheResultHit = "hit"
+	// CacheResultMiss is the result label of the cache miss
+	CacheResultMiss = "miss"
+)
+
+const (
+	// LoginResultSuccess is the result label of the succeeded login
+	LoginResultSuccess = "success"
+	// LoginResultFailure is the result label of the failed login
+	LoginResultFailure = "failure"
+)
+
+// Registry is the registry of the apiserver metrics, it is separated from
+// the registry of the controller-runtime to avoid exposing the controller metrics.
+var Registry = prometheus.NewRegistry()
+
+var collectorGroup = []prometheus.Collector{
+	prometheus.NewGoCollector(),
+	prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	RequestCounter,
+	RequestDurationHistogram,
+	DatastoreOperationDurationHistogram,
+	DatastoreOperationErrorCounter,
+	LoginCounter,
+	ApplicationNumberGauge,
+	ClusterNumberGauge,
+	CacheRequestCounter,
+}
+
+func init() {
+	Registry.MustRegister(collectorGroup...)
+}
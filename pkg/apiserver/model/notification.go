@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+func init() {
+	RegisterModel(&NotificationWebhook{}, &NotificationDelivery{})
+}
+
+const (
+	// EventApplicationDeployed means a new revision of the application is deployed
+	EventApplicationDeployed = "application.deployed"
+	// EventApplicationDeployFailed means the application could not be applied to the cluster
+	EventApplicationDeployFailed = "application.deploy_failed"
+	// EventApplicationRolledBack means the application is rolled back to a history revision
+	EventApplicationRolledBack = "application.rolled_back"
+	// EventWorkflowSucceeded means the workflow of a revision is finished successfully
+	EventWorkflowSucceeded = "workflow.succeeded"
+	// EventWorkflowFailed means the workflow of a revision is terminated
+	EventWorkflowFailed = "workflow.failed"
+	// EventUserCreated means a new user is created
+	EventUserCreated = "user.created"
+	// EventLoginTypeChanged means the login type of the platform is changed
+	EventLoginTypeChanged = "system.login_type_changed"
+	// EventNotificationTest is the event sent by the test API
+	EventNotificationTest = "notification.test"
+)
+
+// NotificationEvents all events could be subscribed by the webhooks
+var NotificationEvents = []string{
+	EventApplicationDeployed,
+	EventApplicationDeployFailed,
+	EventApplicationRolledBack,
+	EventWorkflowSucceeded,
+	EventWorkflowFailed,
+	EventUserCreated,
+	EventLoginTypeChanged,
+}
+
+const (
+	// DeliveryStatusSuccess means the event is delivered
+	DeliveryStatusSuccess = "success"
+	// DeliveryStatusFailure means the event is not delivered after all the attempts
+	DeliveryStatusFailure = "failure"
+)
+
+// NotificationWebhook is the endpoint receiving the platform events
+type NotificationWebhook struct {
+	BaseModel
+	Name        string `json:"name"`
+	Alias       string `json:"alias,omitempty"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+	// Secret is used to sign the payload with HMAC-SHA256
+	Secret string `json:"secret,omitempty"`
+	// Events is the subscribed events, all events are subscribed if empty
+	Events   []string `json:"events,omitempty"`
+	Disabled bool     `json:"disabled"`
+}
+
+// TableName return custom table name
+func (n *NotificationWebhook) TableName() string {
+	return tableNamePrefix + "notification_webhook"
+}
+
+// ShortTableName return custom table name
+func (n *NotificationWebhook) ShortTableName() string {
+	return "ntf_wh"
+}
+
+// PrimaryKey return custom primary key
+func (n *NotificationWebhook) PrimaryKey() string {
+	return n.Name
+}
+
+// Index return custom index
+func (n *NotificationWebhook) Index() map[string]string {
+	index := make(map[string]string)
+	if n.Name != "" {
+		index["name"] = n.Name
+	}
+	return index
+}
+
+// Subscribed checks whether the webhook subscribes the event
+func (n *NotificationWebhook) Subscribed(event string) bool {
+	if len(n.Events) == 0 {
+		return true
+	}
+	for _, e := range n.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationDelivery is the history of delivering an event to a webhook
+type NotificationDelivery struct {
+	BaseModel
+	ID         string `json:"id"`
+	Webhook    string `json:"webhook"`
+	Event      string `json:"event"`
+	Payload    string `json:"payload"`
+	Status     string `json:"status"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Attempts   int    `json:"attempts"`
+	Error      string `json:"error,omitempty"`
+}
+
+// TableName return custom table name
+func (n *NotificationDelivery) TableName() string {
+	return tableNamePrefix + "notification_delivery"
+}
+
+// ShortTableName return custom table name
+func (n *NotificationDelivery) ShortTableName() string {
+	return "ntf_dlv"
+}
+
+// PrimaryKey return custom primary key
+func (n *NotificationDelivery) PrimaryKey() string {
+	return n.ID
+}
+
+// Index return custom index
+func (n *NotificationDelivery) Index() map[string]string {
+	index := make(map[string]string)
+	if n.ID != "" {
+		index["id"] = n.ID
+	}
+	if n.Webhook != "" {
+		index["webhook"] = n.Webhook
+	}
+	if n.Event != "" {
+		index["event"] = n.Event
+	}
+	if n.Status != "" {
+		index["status"] = n.Status
+	}
+	return index
+}
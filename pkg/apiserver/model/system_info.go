@@ -20,6 +20,7 @@ import "time"
 
 func init() {
 	RegisterModel(&SystemInfo{})
+	RegisterModel(&SystemSetup{})
 }
 
 const (
@@ -27,6 +28,8 @@ const (
 	LoginTypeDex string = "dex"
 	// LoginTypeLocal is the local login type
 	LoginTypeLocal string = "local"
+	// LoginTypeOIDC is the generic OpenID Connect login type
+	LoginTypeOIDC string = "oidc"
 )
 
 // SystemInfo systemInfo model
@@ -35,7 +38,49 @@ type SystemInfo struct {
 	InstallID        string        `json:"installID"`
 	EnableCollection bool          `json:"enableCollection"`
 	LoginType        string        `json:"loginType"`
+	OIDCConfig       *OIDCConfig   `json:"oidcConfig,omitempty"`
 	StatisticInfo    StatisticInfo `json:"statisticInfo,omitempty"`
+	// CollectionConfig is the fine-grained control of the collection, all categories are collected if it is empty
+	CollectionConfig *CollectionConfig `json:"collectionConfig,omitempty"`
+	// VelaAddress is the address of VelaUX, it is used to generate the dex config
+	VelaAddress string `json:"velaAddress,omitempty"`
+	// Initialized means the system has been set up by the setup wizard
+	Initialized bool `json:"initialized,omitempty"`
+}
+
+// CollectionConfig controls which categories of the system info are collected
+type CollectionConfig struct {
+	// VersionInfo is the version of KubeVela
+	VersionInfo bool `json:"versionInfo"`
+	// UsageStatistics is the count of applications and clusters, and the enabled addons
+	UsageStatistics bool `json:"usageStatistics"`
+	// DefinitionTopList is the most used definitions
+	DefinitionTopList bool `json:"definitionTopList"`
+	// ExportOnly means the collected info is never reported, it could only be exported to a file
+	ExportOnly bool `json:"exportOnly"`
+}
+
+// GetCollectionConfig returns the collection config, all categories are enabled by default
+func (u *SystemInfo) GetCollectionConfig() CollectionConfig {
+	if u.CollectionConfig == nil {
+		return CollectionConfig{VersionInfo: true, UsageStatistics: true, DefinitionTopList: true}
+	}
+	return *u.CollectionConfig
+}
+
+// OIDCConfig is the config of the generic OpenID Connect provider
+type OIDCConfig struct {
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"clientID"`
+	ClientSecret string   `json:"clientSecret"`
+	RedirectURL  string   `json:"redirectURL"`
+	Scopes       []string `json:"scopes,omitempty"`
+	// EmailClaim is the claim of the ID token used as the user email, default is email
+	EmailClaim string `json:"emailClaim,omitempty"`
+	// NameClaim is the claim of the ID token used as the user name, default is name
+	NameClaim string `json:"nameClaim,omitempty"`
+	// AllowUnverifiedEmail allows the login with the email that the provider marks as not verified
+	AllowUnverifiedEmail bool `json:"allowUnverifiedEmail,omitempty"`
 }
 
 // UpdateDexConfig update dex config
@@ -122,3 +167,37 @@ func (u *SystemInfo) Index() map[string]string {
 	}
 	return index
 }
+
+// SystemSetupName is the name of the only system setup record
+const SystemSetupName = "setup"
+
+// SystemSetup is the marker added when the system is being set up. The datastore refuses to add it twice,
+// so only one of the concurrent setup requests could succeed even if they are served by different replicas.
+type SystemSetup struct {
+	BaseModel
+	Name string `json:"name"`
+}
+
+// TableName return custom table name
+func (s *SystemSetup) TableName() string {
+	return tableNamePrefix + "system_setup"
+}
+
+// ShortTableName is the compressed version of table name for kubeapi storage and others
+func (s *SystemSetup) ShortTableName() string {
+	return "syss"
+}
+
+// PrimaryKey return custom primary key
+func (s *SystemSetup) PrimaryKey() string {
+	return s.Name
+}
+
+// Index return custom index
+func (s *SystemSetup) Index() map[string]string {
+	index := make(map[string]string)
+	if s.Name != "" {
+		index["name"] = s.Name
+	}
+	return index
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import "time"
+
+func init() {
+	RegisterModel(&APIToken{})
+}
+
+const (
+	// APITokenScopeReadOnly means the token can only be used to call the read APIs
+	APITokenScopeReadOnly = "read-only"
+	// APITokenScopeProject means the token can only be used to call the APIs of the bound projects
+	APITokenScopeProject = "project"
+	// APITokenScopeAdmin means the token is not limited by the scope, it has all the permissions of the bound user
+	// but never the ones beyond the user, e.g. the admin token of a normal user could not manage the platform.
+	APITokenScopeAdmin = "admin"
+)
+
+// APIToken is the long-lived token for the automation clients, only the hash of the token secret is stored
+type APIToken struct {
+	BaseModel
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Username    string `json:"username"`
+	Scope       string `json:"scope"`
+	Description string `json:"description,omitempty"`
+	// Projects is the projects bound by the project scoped token
+	Projects     []string  `json:"projects,omitempty"`
+	SecretHash   string    `json:"secretHash"`
+	ExpireTime   time.Time `json:"expireTime,omitempty"`
+	LastUsedTime time.Time `json:"lastUsedTime,omitempty"`
+}
+
+// TableName return custom table name
+func (t *APIToken) TableName() string {
+	return tableNamePrefix + "api_token"
+}
+
+// ShortTableName return custom table name
+func (t *APIToken) ShortTableName() string {
+	return "atk"
+}
+
+// PrimaryKey return custom primary key
+func (t *APIToken) PrimaryKey() string {
+	return t.ID
+}
+
+// Index return custom index
+func (t *APIToken) Index() map[string]string {
+	index := make(map[string]string)
+	if t.ID != "" {
+		index["id"] = t.ID
+	}
+	if t.Username != "" {
+		index["username"] = verifyUserValue(t.Username)
+	}
+	if t.Scope != "" {
+		index["scope"] = t.Scope
+	}
+	return index
+}
+
+// IsExpired checks whether the token is expired, the token without expire time never expires
+func (t *APIToken) IsExpired() bool {
+	return !t.ExpireTime.IsZero() && time.Now().After(t.ExpireTime)
+}
@@ -45,6 +45,8 @@ var (
 	CtxKeyApplicationComponent = "component"
 	// CtxKeyUser request context key of user
 	CtxKeyUser = "user"
+	// CtxKeyAPIToken request context key of the api token used by the request
+	CtxKeyAPIToken = "api-token"
 )
 
 // AddonPhase defines the phase of an addon
@@ -322,17 +324,35 @@ type ClusterBase struct {
 	Reason string `json:"reason"`
 }
 
+// SortOption the sort key and order of the list request
+type SortOption struct {
+	Key string `json:"key"`
+	// Descending means the records are sorted in descending order, default is ascending
+	Descending bool `json:"descending,omitempty"`
+}
+
+// EqualFilter filters the records whose value of the key equals the value
+type EqualFilter struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
 // ListApplicationOptions list application  query options
 type ListApplicationOptions struct {
 	Projects   []string `json:"projects"`
 	Env        string   `json:"env"`
 	TargetName string   `json:"targetName"`
-	Query      string   `json:"query"`
+	// Query searches the name, alias and description of the applications
+	Query    string       `json:"query"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"pageSize"`
+	SortBy   []SortOption `json:"sortBy"`
 }
 
 // ListApplicationResponse list applications by query params
 type ListApplicationResponse struct {
 	Applications []*ApplicationBase `json:"applications"`
+	Total        int64              `json:"total"`
 }
 
 // EnvBindingList env binding list
@@ -799,6 +819,7 @@ type UpdateEnvRequest struct {
 // ListDefinitionResponse list definition response model
 type ListDefinitionResponse struct {
 	Definitions []*DefinitionBase `json:"definitions"`
+	Total       int64             `json:"total"`
 }
 
 // DetailDefinitionResponse get definition detail
@@ -1091,6 +1112,15 @@ type ListTargetResponse struct {
 	Total   int64        `json:"total"`
 }
 
+// ListTargetOptions list target options
+type ListTargetOptions struct {
+	Project string `json:"project"`
+	// Query searches the name, alias and description of the targets
+	Query   string        `json:"query"`
+	Filters []EqualFilter `json:"filters"`
+	SortBy  []SortOption  `json:"sortBy"`
+}
+
 // TargetBase Target base model
 type TargetBase struct {
 	Name         string                 `json:"name"`
@@ -1147,6 +1177,36 @@ type SystemInfo struct {
 	EnableCollection bool      `json:"enableCollection"`
 	LoginType        string    `json:"loginType"`
 	InstallTime      time.Time `json:"installTime,omitempty"`
+	// OIDCConfig is the config of the generic OpenID Connect provider, the client secret is never returned
+	OIDCConfig       *OIDCConfigBase        `json:"oidcConfig,omitempty"`
+	CollectionConfig model.CollectionConfig `json:"collectionConfig"`
+	VelaAddress      string                 `json:"velaAddress,omitempty"`
+}
+
+// OIDCConfigBase is the base info of the generic OpenID Connect provider config
+type OIDCConfigBase struct {
+	Issuer      string   `json:"issuer"`
+	ClientID    string   `json:"clientID"`
+	RedirectURL string   `json:"redirectURL"`
+	Scopes      []string `json:"scopes,omitempty"`
+	EmailClaim  string   `json:"emailClaim,omitempty"`
+	NameClaim   string   `json:"nameClaim,omitempty"`
+	// AllowUnverifiedEmail allows the login with the email that the provider marks as not verified
+	AllowUnverifiedEmail bool `json:"allowUnverifiedEmail,omitempty"`
+}
+
+// OIDCConfigRequest is the request body to set the generic OpenID Connect provider config
+type OIDCConfigRequest struct {
+	Issuer       string   `json:"issuer" validate:"required"`
+	ClientID     string   `json:"clientID" validate:"required"`
+	ClientSecret string   `json:"clientSecret,omitempty" optional:"true"`
+	RedirectURL  string   `json:"redirectURL" validate:"required"`
+	Scopes       []string `json:"scopes,omitempty" optional:"true"`
+	EmailClaim   string   `json:"emailClaim,omitempty" optional:"true"`
+	NameClaim    string   `json:"nameClaim,omitempty" optional:"true"`
+	// AllowUnverifiedEmail allows the login with the email that the provider marks as not verified,
+	// the user with the same email could be taken over by anyone who could set the email in the provider.
+	AllowUnverifiedEmail bool `json:"allowUnverifiedEmail,omitempty" optional:"true"`
 }
 
 // StatisticInfo generated by cronJob running in backend
@@ -1166,6 +1226,80 @@ type SystemInfoRequest struct {
 	EnableCollection bool   `json:"enableCollection"`
 	LoginType        string `json:"loginType"`
 	VelaAddress      string `json:"velaAddress,omitempty"`
+	// OIDCConfig is required when the login type is oidc and not configured before
+	OIDCConfig *OIDCConfigRequest `json:"oidcConfig,omitempty" optional:"true"`
+	// CollectionConfig keeps the current config if it is empty
+	CollectionConfig *model.CollectionConfig `json:"collectionConfig,omitempty" optional:"true"`
+}
+
+// SystemSetupStatusResponse is the response body of the setup status, it could be read without login
+type SystemSetupStatusResponse struct {
+	Initialized bool   `json:"initialized"`
+	LoginType   string `json:"loginType"`
+}
+
+// SystemSetupRequest is the request body that sets up a new installation
+type SystemSetupRequest struct {
+	VelaAddress   string `json:"velaAddress" validate:"required,url"`
+	AdminEmail    string `json:"adminEmail" validate:"required,checkemail"`
+	AdminPassword string `json:"adminPassword" validate:"required,checkpassword"`
+	// LoginType is local if it is empty
+	LoginType string `json:"loginType,omitempty" validate:"omitempty,oneof=local dex oidc" optional:"true"`
+	// OIDCConfig is required when the login type is oidc
+	OIDCConfig       *OIDCConfigRequest      `json:"oidcConfig,omitempty" optional:"true"`
+	EnableCollection bool                    `json:"enableCollection"`
+	CollectionConfig *model.CollectionConfig `json:"collectionConfig,omitempty" optional:"true"`
+}
+
+// CollectionPayload is the system info that would be reported by the collection
+type CollectionPayload struct {
+	PlatformID    string         `json:"platformID"`
+	SystemVersion *SystemVersion `json:"systemVersion,omitempty"`
+	StatisticInfo *StatisticInfo `json:"statisticInfo,omitempty"`
+}
+
+// CollectionPreviewResponse is the response of previewing the collection payload
+type CollectionPreviewResponse struct {
+	EnableCollection bool              `json:"enableCollection"`
+	ExportOnly       bool              `json:"exportOnly"`
+	Payload          CollectionPayload `json:"payload"`
+}
+
+// SystemConfigBundle is the versioned bundle of all state managed by the apiserver, used to backup and restore
+type SystemConfigBundle struct {
+	Version    string    `json:"version"`
+	ExportTime time.Time `json:"exportTime"`
+	// IncludeSecrets means the passwords, client secrets and integration properties are included
+	IncludeSecrets bool                 `json:"includeSecrets"`
+	SystemInfo     *model.SystemInfo    `json:"systemInfo,omitempty"`
+	Users          []*model.User        `json:"users,omitempty"`
+	ProjectUsers   []*model.ProjectUser `json:"projectUsers,omitempty"`
+	Roles          []*model.Role        `json:"roles,omitempty"`
+	Permissions    []*model.Permission  `json:"permissions,omitempty"`
+	Projects       []*model.Project     `json:"projects,omitempty"`
+	Targets        []*model.Target      `json:"targets,omitempty"`
+	Envs           []*model.Env         `json:"envs,omitempty"`
+	Integrations   []*IntegrationBundle `json:"integrations,omitempty"`
+	DexConfig      *model.DexConfig     `json:"dexConfig,omitempty"`
+}
+
+// IntegrationBundle is the integration(config) in the system config bundle
+type IntegrationBundle struct {
+	Name          string `json:"name"`
+	Alias         string `json:"alias,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Project       string `json:"project,omitempty"`
+	ComponentType string `json:"componentType"`
+	// Properties is only exported when the secrets are included
+	Properties string `json:"properties,omitempty"`
+}
+
+// ImportSystemConfigResponse is the response of importing the system config bundle
+type ImportSystemConfigResponse struct {
+	// Imported is the count of the imported records of every kind
+	Imported map[string]int `json:"imported"`
+	// Skipped is the records that can not be imported
+	Skipped []string `json:"skipped,omitempty"`
 }
 
 // SystemVersion contains KubeVela version
@@ -1271,6 +1405,10 @@ type ListUserOptions struct {
 	Name  string `json:"name"`
 	Email string `json:"email"`
 	Alias string `json:"alias"`
+	// Query searches the name, alias and email of the users
+	Query   string        `json:"query"`
+	Filters []EqualFilter `json:"filters"`
+	SortBy  []SortOption  `json:"sortBy"`
 }
 
 // GetLoginTypeResponse get login type response
@@ -1278,6 +1416,42 @@ type GetLoginTypeResponse struct {
 	LoginType string `json:"loginType"`
 }
 
+// CreateAPITokenRequest the request body that create an api token
+type CreateAPITokenRequest struct {
+	Name        string `json:"name" validate:"checkname"`
+	Description string `json:"description,omitempty" optional:"true"`
+	// Scope limits the APIs the token can call, the permissions never exceed the ones of the user who creates the token.
+	// read-only: only the read APIs, project: only the APIs of the bound projects, admin: all the APIs allowed to the user
+	Scope    string   `json:"scope" validate:"oneof=read-only project admin"`
+	Projects []string `json:"projects,omitempty" optional:"true"`
+	// ExpireDays is the valid days of the token, the token never expires if it is zero
+	ExpireDays int `json:"expireDays,omitempty" validate:"min=0" optional:"true"`
+}
+
+// APITokenBase the base info of an api token
+type APITokenBase struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Username     string    `json:"username"`
+	Description  string    `json:"description,omitempty"`
+	Scope        string    `json:"scope"`
+	Projects     []string  `json:"projects,omitempty"`
+	CreateTime   time.Time `json:"createTime"`
+	ExpireTime   time.Time `json:"expireTime,omitempty"`
+	LastUsedTime time.Time `json:"lastUsedTime,omitempty"`
+}
+
+// CreateAPITokenResponse the response body of creating an api token, the token is only returned once
+type CreateAPITokenResponse struct {
+	APITokenBase
+	Token string `json:"token"`
+}
+
+// ListAPITokenResponse the response body that list the api tokens
+type ListAPITokenResponse struct {
+	Tokens []*APITokenBase `json:"tokens"`
+}
+
 // AddProjectUserRequest the request body that add user to project
 type AddProjectUserRequest struct {
 	UserName  string   `json:"userName" validate:"checkname"`
@@ -1365,3 +1539,79 @@ type ChartRepoResponse struct {
 type ChartRepoResponseList struct {
 	ChartRepoResponse []*ChartRepoResponse `json:"repos"`
 }
+
+// CreateNotificationWebhookRequest the request body that create a notification webhook
+type CreateNotificationWebhookRequest struct {
+	Name        string `json:"name" validate:"checkname"`
+	Alias       string `json:"alias,omitempty" validate:"checkalias" optional:"true"`
+	Description string `json:"description,omitempty" optional:"true"`
+	URL         string `json:"url" validate:"required,url"`
+	// Secret is used to sign the payload, the signature is set in the X-Vela-Signature header
+	Secret string `json:"secret,omitempty" optional:"true"`
+	// Events is the subscribed events, all events are subscribed if empty
+	Events   []string `json:"events,omitempty" optional:"true"`
+	Disabled bool     `json:"disabled,omitempty" optional:"true"`
+}
+
+// UpdateNotificationWebhookRequest the request body that update a notification webhook
+type UpdateNotificationWebhookRequest struct {
+	Alias       string `json:"alias,omitempty" validate:"checkalias" optional:"true"`
+	Description string `json:"description,omitempty" optional:"true"`
+	URL         string `json:"url" validate:"required,url"`
+	// Secret keeps the existing secret if it is nil, removes the secret if it is empty
+	Secret   *string  `json:"secret,omitempty" optional:"true"`
+	Events   []string `json:"events,omitempty" optional:"true"`
+	Disabled bool     `json:"disabled,omitempty" optional:"true"`
+}
+
+// NotificationWebhookBase the base info of a notification webhook, the secret is not returned
+type NotificationWebhookBase struct {
+	Name        string    `json:"name"`
+	Alias       string    `json:"alias,omitempty"`
+	Description string    `json:"description,omitempty"`
+	URL         string    `json:"url"`
+	HasSecret   bool      `json:"hasSecret"`
+	Events      []string  `json:"events,omitempty"`
+	Disabled    bool      `json:"disabled"`
+	CreateTime  time.Time `json:"createTime"`
+	UpdateTime  time.Time `json:"updateTime"`
+}
+
+// ListNotificationWebhookResponse the response body that list the notification webhooks
+type ListNotificationWebhookResponse struct {
+	Webhooks []*NotificationWebhookBase `json:"webhooks"`
+	// Events all events could be subscribed
+	Events []string `json:"events"`
+}
+
+// NotificationDeliveryBase the history of delivering an event to a webhook
+type NotificationDeliveryBase struct {
+	ID         string    `json:"id"`
+	Webhook    string    `json:"webhook"`
+	Event      string    `json:"event"`
+	Payload    string    `json:"payload"`
+	Status     string    `json:"status"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Attempts   int       `json:"attempts"`
+	Error      string    `json:"error,omitempty"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+// ListNotificationDeliveryResponse the response body that list the delivery history of a webhook
+type ListNotificationDeliveryResponse struct {
+	Deliveries []*NotificationDeliveryBase `json:"deliveries"`
+	Total      int64                       `json:"total"`
+}
+
+// TestNotificationRequest the request body that send a test event to a webhook
+type TestNotificationRequest struct {
+	Webhook string `json:"webhook" validate:"checkname"`
+}
+
+// NotificationPayload the body posted to the notification webhooks
+type NotificationPayload struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/oam-dev/kubevela/pkg/apiserver/collect"
@@ -28,6 +30,7 @@ import (
 	restfulspec "github.com/emicklei/go-restful-openapi/v2"
 	"github.com/emicklei/go-restful/v3"
 	"github.com/go-openapi/spec"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/klog/v2"
@@ -38,6 +41,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/apiserver/datastore/kubeapi"
 	"github.com/oam-dev/kubevela/pkg/apiserver/datastore/mongodb"
 	"github.com/oam-dev/kubevela/pkg/apiserver/log"
+	"github.com/oam-dev/kubevela/pkg/apiserver/metrics"
 	"github.com/oam-dev/kubevela/pkg/apiserver/rest/usecase"
 	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils"
 	"github.com/oam-dev/kubevela/pkg/apiserver/rest/webservice"
@@ -65,6 +69,9 @@ type Config struct {
 
 	// DisableStatisticCronJob close the calculate system info cronJob
 	DisableStatisticCronJob bool
+
+	// DisableCache disables the in-memory cache of the read-heavy usecases
+	DisableCache bool
 }
 
 type leaderConfig struct {
@@ -108,13 +115,14 @@ func New(cfg Config) (a APIServer, err error) {
 	s := &restServer{
 		webContainer: restful.NewContainer(),
 		cfg:          cfg,
-		dataStore:    ds,
+		dataStore:    datastore.WithMetrics(ds),
 	}
 	return s, nil
 }
 
 func (s *restServer) Run(ctx context.Context) error {
 	s.RegisterServices(ctx, true)
+	collect.StartResourceMetrics(ctx, s.dataStore)
 
 	l, err := s.setupLeaderElection()
 	if err != nil {
@@ -189,7 +197,7 @@ func (s *restServer) runWorkflowRecordSync(ctx context.Context, duration time.Du
 
 // RegisterServices register web service
 func (s *restServer) RegisterServices(ctx context.Context, initDatabase bool) restfulspec.Config {
-	s.usecases = webservice.Init(ctx, s.dataStore, s.cfg.AddonCacheTime, initDatabase)
+	s.usecases = webservice.Init(ctx, s.dataStore, s.cfg.AddonCacheTime, s.cfg.DisableCache, initDatabase)
 
 	/* **************************************************************  */
 	/* *************       Open API Route Group     *****************  */
@@ -210,11 +218,22 @@ func (s *restServer) RegisterServices(ctx context.Context, initDatabase bool) re
 	// Add request log
 	s.webContainer.Filter(s.requestLog)
 
+	// Add request metrics
+	s.webContainer.Filter(s.requestMetrics)
+
+	// Validate the api tokens, the authCheckFilter of the routes reads the validated token
+	s.webContainer.Filter(webservice.NewAPITokenFilter(s.usecases["token"].(usecase.TokenUsecase)))
+
 	// Register all custom webservice
 	for _, handler := range webservice.GetRegisteredWebService() {
 		s.webContainer.Add(handler.GetWebService())
 	}
 
+	// Expose the metrics, the path is out of the restful routes so it is not authenticated
+	if s.cfg.MetricPath != "" {
+		s.webContainer.Handle(s.cfg.MetricPath, promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}))
+	}
+
 	config := restfulspec.Config{
 		WebServices:                   s.webContainer.RegisteredWebServices(), // you control what services are visible
 		APIPath:                       "/apidocs.json",
@@ -239,6 +258,27 @@ func (s *restServer) requestLog(req *restful.Request, resp *restful.Response, ch
 	).Infof("request log")
 }
 
+func (s *restServer) requestMetrics(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	start := time.Now()
+	chain.ProcessFilter(req, resp)
+	ws := s.matchWebServicePath(req.Request.URL.Path)
+	metrics.RequestCounter.WithLabelValues(ws, req.Request.Method, strconv.Itoa(resp.StatusCode())).Inc()
+	metrics.RequestDurationHistogram.WithLabelValues(ws, req.Request.Method).Observe(time.Since(start).Seconds())
+}
+
+// matchWebServicePath returns the root path of the webservice that serves the request path,
+// the longest root path is matched because some root paths are nested, e.g. /api/v1 and /api/v1/users.
+func (s *restServer) matchWebServicePath(path string) string {
+	matched := "unknown"
+	for _, ws := range s.webContainer.RegisteredWebServices() {
+		root := ws.RootPath()
+		if (path == root || strings.HasPrefix(path, root+"/")) && (matched == "unknown" || len(root) > len(matched)) {
+			matched = root
+		}
+	}
+	return matched
+}
+
 func enrichSwaggerObject(swo *spec.Swagger) {
 	swo.Info = &spec.Info{
 		InfoProps: spec.InfoProps{
@@ -97,8 +97,12 @@ func AddonImpl2AddonRes(impl *pkgaddon.UIData) (*apis.DetailAddonResponse, error
 	}, nil
 }
 
+// addonCatalogCacheTTL is how long the addon registries and catalogs are cached,
+// the catalog is also refreshed by the addon cache in the background.
+const addonCatalogCacheTTL = time.Minute
+
 // NewAddonUsecase returns an addon usecase
-func NewAddonUsecase(cacheTime time.Duration) AddonHandler {
+func NewAddonUsecase(cacheTime time.Duration, cache *Cache) AddonHandler {
 	config, err := clients.GetKubeConfig()
 	if err != nil {
 		panic(err)
@@ -112,19 +116,20 @@ func NewAddonUsecase(cacheTime time.Duration) AddonHandler {
 		panic(err)
 	}
 	ds := pkgaddon.NewRegistryDataStore(kubecli)
-	cache := pkgaddon.NewCache(ds)
+	registryCache := pkgaddon.NewCache(ds)
 
 	// TODO(@wonderflow): it's better to add a close channel here, but it should be fine as it's only invoke once in APIServer.
-	go cache.DiscoverAndRefreshLoop(cacheTime)
+	go registryCache.DiscoverAndRefreshLoop(cacheTime)
 
 	return &defaultAddonHandler{
-		addonRegistryCache: cache,
+		addonRegistryCache: registryCache,
 		addonRegistryDS:    ds,
 		kubeClient:         kubecli,
 		config:             config,
 		apply:              apply.NewAPIApplicator(kubecli),
 		mutex:              new(sync.RWMutex),
 		discoveryClient:    dc,
+		cache:              cache,
 	}
 }
 
@@ -135,6 +140,7 @@ type defaultAddonHandler struct {
 	config             *rest.Config
 	apply              apply.Applicator
 	discoveryClient    *discovery.DiscoveryClient
+	cache              *Cache
 
 	mutex *sync.RWMutex
 }
@@ -144,7 +150,7 @@ func (u *defaultAddonHandler) GetAddon(ctx context.Context, name string, registr
 	var addon *pkgaddon.UIData
 	var err error
 	if registry == "" {
-		registries, err := u.addonRegistryDS.ListRegistries(ctx)
+		registries, err := u.listRegistries(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -237,18 +243,39 @@ func (u *defaultAddonHandler) StatusAddon(ctx context.Context, name string) (*ap
 }
 
 func (u *defaultAddonHandler) ListAddons(ctx context.Context, registry, query string) ([]*apis.DetailAddonResponse, error) {
+	addons, err := u.listCatalog(ctx, registry)
+	if query == "" {
+		return addons, err
+	}
+	var filtered []*apis.DetailAddonResponse
+	for _, addon := range addons {
+		if strings.Contains(addon.Name, query) || strings.Contains(addon.Description, query) {
+			filtered = append(filtered, addon)
+		}
+	}
+	return filtered, err
+}
+
+// listCatalog lists the visible addons of the registry, or of all registries if it is empty.
+// The catalog is cached by the known registries only, the query is filtered after the lookup.
+func (u *defaultAddonHandler) listCatalog(ctx context.Context, registry string) ([]*apis.DetailAddonResponse, error) {
+	catalogKey := "catalog/registry:" + registry
+	if data, ok := u.cache.Get(cacheGroupAddon, catalogKey); ok {
+		return data.([]*apis.DetailAddonResponse), nil
+	}
 	var addons []*pkgaddon.UIData
-	rs, err := u.addonRegistryDS.ListRegistries(ctx)
+	rs, err := u.listRegistries(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var gatherErr velaerr.ErrorList
-
+	found := registry == ""
 	for _, r := range rs {
 		if registry != "" && r.Name != registry {
 			continue
 		}
+		found = true
 		listAddons, err := u.addonRegistryCache.ListUIData(r)
 		if err != nil {
 			gatherErr = append(gatherErr, err)
@@ -256,6 +283,9 @@ func (u *defaultAddonHandler) ListAddons(ctx context.Context, registry, query st
 		}
 		addons = mergeAddons(addons, listAddons)
 	}
+	if !found {
+		return nil, nil
+	}
 
 	for i, a := range addons {
 		if a.Invisible {
@@ -263,15 +293,6 @@ func (u *defaultAddonHandler) ListAddons(ctx context.Context, registry, query st
 		}
 	}
 
-	if query != "" {
-		var filtered []*pkgaddon.UIData
-		for i, addon := range addons {
-			if strings.Contains(addon.Name, query) || strings.Contains(addon.Description, query) {
-				filtered = append(filtered, addons[i])
-			}
-		}
-		addons = filtered
-	}
 	sort.Slice(addons, func(i, j int) bool {
 		return addons[i].Name < addons[j].Name
 	})
@@ -293,11 +314,29 @@ func (u *defaultAddonHandler) ListAddons(ctx context.Context, registry, query st
 	if gatherErr.HasError() {
 		return addonResources, gatherErr
 	}
+	u.cache.Put(cacheGroupAddon, catalogKey, addonResources, addonCatalogCacheTTL)
 	return addonResources, nil
 }
 
+// listRegistries lists the addon registries, the result is cached until the registries are modified
+func (u *defaultAddonHandler) listRegistries(ctx context.Context) ([]pkgaddon.Registry, error) {
+	if data, ok := u.cache.Get(cacheGroupAddon, "registries"); ok {
+		return data.([]pkgaddon.Registry), nil
+	}
+	registries, err := u.addonRegistryDS.ListRegistries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u.cache.Put(cacheGroupAddon, "registries", registries, addonCatalogCacheTTL)
+	return registries, nil
+}
+
 func (u *defaultAddonHandler) DeleteAddonRegistry(ctx context.Context, name string) error {
-	return u.addonRegistryDS.DeleteRegistry(ctx, name)
+	if err := u.addonRegistryDS.DeleteRegistry(ctx, name); err != nil {
+		return err
+	}
+	u.cache.Invalidate(cacheGroupAddon)
+	return nil
 }
 
 func (u *defaultAddonHandler) CreateAddonRegistry(ctx context.Context, req apis.CreateAddonRegistryRequest) (*apis.AddonRegistry, error) {
@@ -307,6 +346,7 @@ func (u *defaultAddonHandler) CreateAddonRegistry(ctx context.Context, req apis.
 	if err != nil {
 		return nil, err
 	}
+	u.cache.Invalidate(cacheGroupAddon)
 
 	return convertAddonRegistry(r), nil
 }
@@ -352,6 +392,7 @@ func (u defaultAddonHandler) UpdateAddonRegistry(ctx context.Context, name strin
 	if err != nil {
 		return nil, err
 	}
+	u.cache.Invalidate(cacheGroupAddon)
 
 	return convertAddonRegistry(r), nil
 }
@@ -359,7 +400,7 @@ func (u defaultAddonHandler) UpdateAddonRegistry(ctx context.Context, name strin
 func (u *defaultAddonHandler) ListAddonRegistries(ctx context.Context) ([]*apis.AddonRegistry, error) {
 
 	var list []*apis.AddonRegistry
-	registries, err := u.addonRegistryDS.ListRegistries(ctx)
+	registries, err := u.listRegistries(ctx)
 	if err != nil {
 		// the storage configmap still not exist, don't return error add registry will create the configmap
 		if errors2.IsNotFound(err) {
@@ -22,8 +22,6 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
-	"sort"
-	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -70,7 +68,7 @@ const (
 
 // ApplicationUsecase application usecase
 type ApplicationUsecase interface {
-	ListApplications(ctx context.Context, listOptions apisv1.ListApplicationOptions) ([]*apisv1.ApplicationBase, error)
+	ListApplications(ctx context.Context, listOptions apisv1.ListApplicationOptions) (*apisv1.ListApplicationResponse, error)
 	GetApplication(ctx context.Context, appName string) (*model.Application, error)
 	GetApplicationStatus(ctx context.Context, app *model.Application, envName string) (*common.AppStatus, error)
 	DetailApplication(ctx context.Context, app *model.Application) (*apisv1.DetailApplicationResponse, error)
@@ -106,17 +104,18 @@ type ApplicationUsecase interface {
 }
 
 type applicationUsecaseImpl struct {
-	ds                datastore.DataStore
-	kubeClient        client.Client
-	kubeConfig        *rest.Config
-	apply             apply.Applicator
-	workflowUsecase   WorkflowUsecase
-	envUsecase        EnvUsecase
-	envBindingUsecase EnvBindingUsecase
-	targetUsecase     TargetUsecase
-	definitionUsecase DefinitionUsecase
-	projectUsecase    ProjectUsecase
-	userUsecase       UserUsecase
+	ds                  datastore.DataStore
+	kubeClient          client.Client
+	kubeConfig          *rest.Config
+	apply               apply.Applicator
+	workflowUsecase     WorkflowUsecase
+	envUsecase          EnvUsecase
+	envBindingUsecase   EnvBindingUsecase
+	targetUsecase       TargetUsecase
+	definitionUsecase   DefinitionUsecase
+	projectUsecase      ProjectUsecase
+	userUsecase         UserUsecase
+	notificationUsecase NotificationUsecase
 }
 
 // NewApplicationUsecase new application usecase
@@ -128,6 +127,7 @@ func NewApplicationUsecase(ds datastore.DataStore,
 	definitionUsecase DefinitionUsecase,
 	projectUsecase ProjectUsecase,
 	userUsecase UserUsecase,
+	notificationUsecase NotificationUsecase,
 ) ApplicationUsecase {
 	kubecli, err := clients.GetKubeClient()
 	if err != nil {
@@ -138,31 +138,24 @@ func NewApplicationUsecase(ds datastore.DataStore,
 		log.Logger.Fatalf("get kube rest config failure %s", err.Error())
 	}
 	return &applicationUsecaseImpl{
-		ds:                ds,
-		workflowUsecase:   workflowUsecase,
-		envBindingUsecase: envBindingUsecase,
-		targetUsecase:     targetUsecase,
-		kubeClient:        kubecli,
-		kubeConfig:        config,
-		apply:             apply.NewAPIApplicator(kubecli),
-		definitionUsecase: definitionUsecase,
-		projectUsecase:    projectUsecase,
-		envUsecase:        envUsecase,
-		userUsecase:       userUsecase,
+		ds:                  ds,
+		workflowUsecase:     workflowUsecase,
+		envBindingUsecase:   envBindingUsecase,
+		targetUsecase:       targetUsecase,
+		kubeClient:          kubecli,
+		kubeConfig:          config,
+		apply:               apply.NewAPIApplicator(kubecli),
+		definitionUsecase:   definitionUsecase,
+		projectUsecase:      projectUsecase,
+		envUsecase:          envUsecase,
+		userUsecase:         userUsecase,
+		notificationUsecase: notificationUsecase,
 	}
 }
 
-func listApp(ctx context.Context, ds datastore.DataStore, listOptions apisv1.ListApplicationOptions) ([]*model.Application, error) {
-	var app = model.Application{}
-	var err error
-	var envBinding []*apisv1.EnvBindingBase
-	if listOptions.Env != "" || listOptions.TargetName != "" {
-		envBinding, err = listFullEnvBinding(ctx, ds, envListOption{})
-		if err != nil {
-			log.Logger.Errorf("list envbinding for list application in env %s err %v", utils2.Sanitize(listOptions.Env), err)
-			return nil, err
-		}
-	}
+// applicationFilterOptions converts the list options to the datastore filter options,
+// it returns nil if there is no application deployed to the env or target.
+func applicationFilterOptions(ctx context.Context, ds datastore.DataStore, listOptions apisv1.ListApplicationOptions) (*datastore.FilterOptions, error) {
 	var filterOptions datastore.FilterOptions
 	if len(listOptions.Projects) > 0 {
 		filterOptions.In = append(filterOptions.In, datastore.InQueryOption{
@@ -170,7 +163,67 @@ func listApp(ctx context.Context, ds datastore.DataStore, listOptions apisv1.Lis
 			Values: listOptions.Projects,
 		})
 	}
-	entities, err := ds.List(ctx, &app, &datastore.ListOptions{FilterOptions: filterOptions})
+	if listOptions.Query != "" {
+		filterOptions.Keywords = append(filterOptions.Keywords, datastore.KeywordQueryOption{
+			Keys:    []string{"name", "alias", "description"},
+			Keyword: listOptions.Query,
+		})
+	}
+	if listOptions.Env == "" && listOptions.TargetName == "" {
+		return &filterOptions, nil
+	}
+	envBindings, err := listEnvBindings(ctx, ds, envListOption{envName: listOptions.Env})
+	if err != nil {
+		log.Logger.Errorf("list envbinding for list application in env %s err %v", utils2.Sanitize(listOptions.Env), err)
+		return nil, err
+	}
+	var envNames []string
+	if listOptions.TargetName != "" {
+		envs, err := listEnvs(ctx, ds, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, env := range envs {
+			if utils.StringsContain(env.Targets, listOptions.TargetName) {
+				envNames = append(envNames, env.Name)
+			}
+		}
+	}
+	var appNames []string
+	for _, eb := range envBindings {
+		if listOptions.TargetName != "" && !utils.StringsContain(envNames, eb.Name) {
+			continue
+		}
+		if !utils.StringsContain(appNames, eb.AppPrimaryKey) {
+			appNames = append(appNames, eb.AppPrimaryKey)
+		}
+	}
+	if len(appNames) == 0 {
+		return nil, nil
+	}
+	filterOptions.In = append(filterOptions.In, datastore.InQueryOption{
+		Key:    "name",
+		Values: appNames,
+	})
+	return &filterOptions, nil
+}
+
+func applicationListOptions(filterOptions datastore.FilterOptions, listOptions apisv1.ListApplicationOptions) *datastore.ListOptions {
+	sortBy := convertSortOptions(listOptions.SortBy, datastore.SortOption{Key: "updateTime", Order: datastore.SortOrderDescending})
+	return &datastore.ListOptions{
+		FilterOptions: filterOptions,
+		Page:          listOptions.Page,
+		PageSize:      listOptions.PageSize,
+		SortBy:        sortBy,
+	}
+}
+
+func listApp(ctx context.Context, ds datastore.DataStore, listOptions apisv1.ListApplicationOptions) ([]*model.Application, error) {
+	filterOptions, err := applicationFilterOptions(ctx, ds, listOptions)
+	if err != nil || filterOptions == nil {
+		return nil, err
+	}
+	entities, err := ds.List(ctx, &model.Application{}, applicationListOptions(*filterOptions, listOptions))
 	if err != nil {
 		return nil, err
 	}
@@ -180,38 +233,13 @@ func listApp(ctx context.Context, ds datastore.DataStore, listOptions apisv1.Lis
 		if !ok {
 			continue
 		}
-		if listOptions.Query != "" &&
-			!(strings.Contains(appModel.Alias, listOptions.Query) ||
-				strings.Contains(appModel.Name, listOptions.Query) ||
-				strings.Contains(appModel.Description, listOptions.Query)) {
-			continue
-		}
-		if listOptions.TargetName != "" {
-			targetIsContain, _ := CheckAppEnvBindingsContainTarget(envBinding, listOptions.TargetName)
-			if !targetIsContain {
-				continue
-			}
-		}
-		if len(envBinding) > 0 && listOptions.Env != "" {
-			check := func() bool {
-				for _, eb := range envBinding {
-					if eb.Name == listOptions.Env && appModel.PrimaryKey() == eb.AppDeployName {
-						return true
-					}
-				}
-				return false
-			}
-			if !check() {
-				continue
-			}
-		}
 		list = append(list, appModel)
 	}
 	return list, nil
 }
 
 // ListApplications list applications
-func (c *applicationUsecaseImpl) ListApplications(ctx context.Context, listOptions apisv1.ListApplicationOptions) ([]*apisv1.ApplicationBase, error) {
+func (c *applicationUsecaseImpl) ListApplications(ctx context.Context, listOptions apisv1.ListApplicationOptions) (*apisv1.ListApplicationResponse, error) {
 	userName, ok := ctx.Value(&apisv1.CtxKeyUser).(string)
 	if !ok {
 		return nil, bcode.ErrUnauthorized
@@ -224,30 +252,42 @@ func (c *applicationUsecaseImpl) ListApplications(ctx context.Context, listOptio
 	for _, project := range projects {
 		availableProjectNames = append(availableProjectNames, project.Name)
 	}
+	resp := &apisv1.ListApplicationResponse{Applications: []*apisv1.ApplicationBase{}}
 	if len(availableProjectNames) == 0 {
-		return []*apisv1.ApplicationBase{}, nil
+		return resp, nil
 	}
 	if len(listOptions.Projects) > 0 {
 		if !utils2.SliceIncludeSlice(availableProjectNames, listOptions.Projects) {
-			return []*apisv1.ApplicationBase{}, nil
+			return resp, nil
 		}
 	}
 	if len(listOptions.Projects) == 0 {
 		listOptions.Projects = availableProjectNames
 	}
-	apps, err := listApp(ctx, c.ds, listOptions)
+	filterOptions, err := applicationFilterOptions(ctx, c.ds, listOptions)
 	if err != nil {
 		return nil, err
 	}
-	var list []*apisv1.ApplicationBase
-	for _, app := range apps {
-		appBase := c.convertAppModelToBase(app, projects)
-		list = append(list, appBase)
+	if filterOptions == nil {
+		return resp, nil
 	}
-	sort.Slice(list, func(i, j int) bool {
-		return list[i].UpdateTime.Unix() > list[j].UpdateTime.Unix()
-	})
-	return list, nil
+	entities, err := c.ds.List(ctx, &model.Application{}, applicationListOptions(*filterOptions, listOptions))
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range entities {
+		if app, ok := entity.(*model.Application); ok {
+			resp.Applications = append(resp.Applications, c.convertAppModelToBase(app, projects))
+		}
+	}
+	resp.Total = int64(len(resp.Applications))
+	if listOptions.Page > 0 && listOptions.PageSize > 0 {
+		resp.Total, err = c.ds.Count(ctx, &model.Application{}, filterOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
 }
 
 // GetApplication get application model
@@ -760,6 +800,9 @@ func (c *applicationUsecaseImpl) Deploy(ctx context.Context, app *model.Applicat
 		}
 
 		log.Logger.Errorf("deploy app %s failure %s", app.PrimaryKey(), err.Error())
+		data := revisionEventData(app, appRevision)
+		data["reason"] = appRevision.Reason
+		emitNotification(ctx, c.notificationUsecase, model.EventApplicationDeployFailed, data)
 		return nil, bcode.ErrDeployApplyFail
 	}
 
@@ -774,11 +817,26 @@ func (c *applicationUsecaseImpl) Deploy(ctx context.Context, app *model.Applicat
 		log.Logger.Warnf("update app revision failure %s", err.Error())
 	}
 
+	emitNotification(ctx, c.notificationUsecase, model.EventApplicationDeployed, revisionEventData(app, appRevision))
+
 	return &apisv1.ApplicationDeployResponse{
 		ApplicationRevisionBase: c.convertRevisionModelToBase(ctx, appRevision),
 	}, nil
 }
 
+// revisionEventData is the data of the notification events about the deployment of a revision
+func revisionEventData(app *model.Application, appRevision *model.ApplicationRevision) map[string]string {
+	return map[string]string{
+		"application": app.Name,
+		"project":     app.Project,
+		"revision":    appRevision.Version,
+		"workflow":    appRevision.WorkflowName,
+		"envName":     appRevision.EnvName,
+		"deployUser":  appRevision.DeployUser,
+		"triggerType": appRevision.TriggerType,
+	}
+}
+
 // sync configs to clusters
 func (c *applicationUsecaseImpl) syncConfigs4Application(ctx context.Context, app *v1beta1.Application, projectName, envName string) error {
 	var areTerraformComponents = true
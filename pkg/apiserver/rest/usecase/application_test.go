@@ -18,6 +18,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -39,7 +40,6 @@ import (
 	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
 	"github.com/oam-dev/kubevela/pkg/apiserver/model"
 	v1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
-	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils"
 	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
@@ -77,7 +77,7 @@ var _ = Describe("Test application usecase function", func() {
 		projectUsecase = &projectUsecaseImpl{ds: ds, k8sClient: k8sClient, rbacUsecase: rbacUsecase}
 		envUsecase = &envUsecaseImpl{ds: ds, kubeClient: k8sClient, projectUsecase: projectUsecase}
 		workflowUsecase = &workflowUsecaseImpl{ds: ds, envUsecase: envUsecase}
-		definitionUsecase = &definitionUsecaseImpl{kubeClient: k8sClient, caches: utils.NewMemoryCacheStore(context.Background())}
+		definitionUsecase = &definitionUsecaseImpl{kubeClient: k8sClient, cache: NewCache(context.Background(), true)}
 		envBindingUsecase = &envBindingUsecaseImpl{ds: ds, envUsecase: envUsecase, workflowUsecase: workflowUsecase, kubeClient: k8sClient, definitionUsecase: definitionUsecase}
 		targetUsecase = &targetUsecaseImpl{ds: ds, k8sClient: k8sClient}
 		appUsecase = &applicationUsecaseImpl{
@@ -187,7 +187,8 @@ var _ = Describe("Test application usecase function", func() {
 			Projects:   []string{testProject},
 			TargetName: defaultTarget})
 		Expect(err).Should(BeNil())
-		Expect(cmp.Diff(len(list), 1)).Should(BeEmpty())
+		Expect(cmp.Diff(len(list.Applications), 1)).Should(BeEmpty())
+		Expect(cmp.Diff(list.Total, int64(1))).Should(BeEmpty())
 	})
 
 	It("Test DetailApplication function", func() {
@@ -447,6 +448,26 @@ var _ = Describe("Test application usecase function", func() {
 		Expect(err).Should(BeNil())
 	})
 
+	It("Test the deploy failure is notified", func() {
+		appModel, err := appUsecase.GetApplication(context.TODO(), testApp)
+		Expect(err).Should(BeNil())
+		notification := &fakeNotificationUsecase{}
+		failedUsecase := *appUsecase
+		failedUsecase.apply = failedApplicator{}
+		failedUsecase.notificationUsecase = notification
+		_, err = failedUsecase.Deploy(context.TODO(), appModel, v1.ApplicationDeployRequest{WorkflowName: convertWorkflowName("app-dev"), Force: true})
+		Expect(err).Should(Equal(bcode.ErrDeployApplyFail))
+		Expect(notification.events).Should(Equal([]string{model.EventApplicationDeployFailed}))
+
+		By("terminate the failed revision, so the following deploys are not conflicted")
+		revisions, err := appUsecase.ds.List(context.TODO(), &model.ApplicationRevision{AppPrimaryKey: appModel.PrimaryKey(), Status: model.RevisionStatusFail}, nil)
+		Expect(err).Should(BeNil())
+		Expect(len(revisions)).Should(Equal(1))
+		revision := revisions[0].(*model.ApplicationRevision)
+		revision.Status = model.RevisionStatusTerminated
+		Expect(appUsecase.ds.Put(context.TODO(), revision)).Should(Succeed())
+	})
+
 	It("Test ListRecords function", func() {
 		By("no running records in application")
 		ctx := context.TODO()
@@ -756,3 +777,10 @@ func createTestSuspendApp(ctx context.Context, appName, envName, revisionVersion
 
 	return testapp, nil
 }
+
+// failedApplicator always fails to apply the resources
+type failedApplicator struct{}
+
+func (failedApplicator) Apply(context.Context, client.Object, ...apply.ApplyOption) error {
+	return errors.New("apply failure")
+}
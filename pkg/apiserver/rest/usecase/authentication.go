@@ -40,6 +40,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/apiserver/clients"
 	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
 	"github.com/oam-dev/kubevela/pkg/apiserver/log"
+	"github.com/oam-dev/kubevela/pkg/apiserver/metrics"
 	"github.com/oam-dev/kubevela/pkg/apiserver/model"
 	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
 	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
@@ -50,8 +51,15 @@ const (
 	dexConfigName      = "dex-config"
 	secretDexConfigKey = "config.yaml"
 	dexAddonName       = "addon-dex"
+	// defaultVelaAddress is used by the dex config until the address of VelaUX is set
+	defaultVelaAddress = "http://velaux.com"
 	jwtIssuer          = "vela-issuer"
 
+	defaultOIDCEmailClaim = "email"
+	defaultOIDCNameClaim  = "name"
+	// oidcEmailVerifiedClaim is the standard claim of whether the email is verified by the provider
+	oidcEmailVerifiedClaim = "email_verified"
+
 	// GrantTypeAccess is the grant type for access token
 	GrantTypeAccess = "access"
 	// GrantTypeRefresh is the grant type for refresh token
@@ -65,27 +73,30 @@ type AuthenticationUsecase interface {
 	Login(ctx context.Context, loginReq apisv1.LoginRequest) (*apisv1.LoginResponse, error)
 	RefreshToken(ctx context.Context, refreshToken string) (*apisv1.RefreshTokenResponse, error)
 	GetDexConfig(ctx context.Context) (*apisv1.DexConfigResponse, error)
+	GetOIDCConfig(ctx context.Context) (*apisv1.OIDCConfigBase, error)
 	GetLoginType(ctx context.Context) (*apisv1.GetLoginTypeResponse, error)
 }
 
 type authenticationUsecaseImpl struct {
-	sysUsecase  SystemInfoUsecase
-	userUsecase UserUsecase
-	ds          datastore.DataStore
-	kubeClient  client.Client
+	sysUsecase          SystemInfoUsecase
+	userUsecase         UserUsecase
+	notificationUsecase NotificationUsecase
+	ds                  datastore.DataStore
+	kubeClient          client.Client
 }
 
 // NewAuthenticationUsecase new authentication usecase
-func NewAuthenticationUsecase(ds datastore.DataStore, sysUsecase SystemInfoUsecase, userUsecase UserUsecase) AuthenticationUsecase {
+func NewAuthenticationUsecase(ds datastore.DataStore, sysUsecase SystemInfoUsecase, userUsecase UserUsecase, notificationUsecase NotificationUsecase) AuthenticationUsecase {
 	kubecli, err := clients.GetKubeClient()
 	if err != nil {
 		log.Logger.Fatalf("failed to get kube client: %s", err.Error())
 	}
 	return &authenticationUsecaseImpl{
-		sysUsecase:  sysUsecase,
-		userUsecase: userUsecase,
-		ds:          ds,
-		kubeClient:  kubecli,
+		sysUsecase:          sysUsecase,
+		userUsecase:         userUsecase,
+		notificationUsecase: notificationUsecase,
+		ds:                  ds,
+		kubeClient:          kubecli,
 	}
 }
 
@@ -94,8 +105,19 @@ type authHandler interface {
 }
 
 type dexHandlerImpl struct {
-	idToken *oidc.IDToken
-	ds      datastore.DataStore
+	idToken             *oidc.IDToken
+	ds                  datastore.DataStore
+	notificationUsecase NotificationUsecase
+}
+
+type oidcHandlerImpl struct {
+	idToken             *oidc.IDToken
+	ds                  datastore.DataStore
+	notificationUsecase NotificationUsecase
+	emailClaim          string
+	nameClaim           string
+	// allowUnverifiedEmail allows the email marked as not verified to match the existing user
+	allowUnverifiedEmail bool
 }
 
 type localHandlerImpl struct {
@@ -134,8 +156,55 @@ func (a *authenticationUsecaseImpl) newDexHandler(ctx context.Context, req apisv
 		return nil, err
 	}
 	return &dexHandlerImpl{
-		idToken: idToken,
-		ds:      a.ds,
+		idToken:             idToken,
+		ds:                  a.ds,
+		notificationUsecase: a.notificationUsecase,
+	}, nil
+}
+
+func (a *authenticationUsecaseImpl) newOIDCHandler(ctx context.Context, req apisv1.LoginRequest, config *model.OIDCConfig) (*oidcHandlerImpl, error) {
+	if req.Code == "" {
+		return nil, bcode.ErrInvalidLoginRequest
+	}
+	if config == nil {
+		return nil, bcode.ErrOIDCConfigNotFound
+	}
+	// the provider endpoints and keys are resolved from the standard discovery document of the issuer
+	provider, err := oidc.NewProvider(ctx, config.Issuer)
+	if err != nil {
+		log.Logger.Errorf("failed to discover the oidc provider %s: %s", config.Issuer, err.Error())
+		return nil, bcode.ErrInvalidOIDCConfig
+	}
+	idTokenVerifier := provider.Verifier(&oidc.Config{ClientID: config.ClientID})
+	oauth2Config := &oauth2.Config{
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  config.RedirectURL,
+		Scopes:       getOIDCScopes(config),
+	}
+	oidcCtx := oidc.ClientContext(ctx, http.DefaultClient)
+	token, err := oauth2Config.Exchange(oidcCtx, req.Code)
+	if err != nil {
+		log.Logger.Errorf("failed to exchange the code with the oidc provider %s: %s", config.Issuer, err.Error())
+		return nil, bcode.ErrOIDCLoginFailed
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, bcode.ErrInvalidLoginRequest
+	}
+	idToken, err := idTokenVerifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		log.Logger.Errorf("failed to verify the id token issued by the oidc provider %s: %s", config.Issuer, err.Error())
+		return nil, bcode.ErrOIDCLoginFailed
+	}
+	return &oidcHandlerImpl{
+		idToken:              idToken,
+		ds:                   a.ds,
+		notificationUsecase:  a.notificationUsecase,
+		emailClaim:           config.EmailClaim,
+		nameClaim:            config.NameClaim,
+		allowUnverifiedEmail: config.AllowUnverifiedEmail,
 	}, nil
 }
 
@@ -152,12 +221,22 @@ func (a *authenticationUsecaseImpl) newLocalHandler(req apisv1.LoginRequest) (*l
 }
 
 func (a *authenticationUsecaseImpl) Login(ctx context.Context, loginReq apisv1.LoginRequest) (*apisv1.LoginResponse, error) {
-	var handler authHandler
-	var err error
 	sysInfo, err := a.sysUsecase.Get(ctx)
 	if err != nil {
 		return nil, err
 	}
+	resp, err := a.login(ctx, sysInfo, loginReq)
+	if err != nil {
+		metrics.LoginCounter.WithLabelValues(sysInfo.LoginType, metrics.LoginResultFailure).Inc()
+		return nil, err
+	}
+	metrics.LoginCounter.WithLabelValues(sysInfo.LoginType, metrics.LoginResultSuccess).Inc()
+	return resp, nil
+}
+
+func (a *authenticationUsecaseImpl) login(ctx context.Context, sysInfo *model.SystemInfo, loginReq apisv1.LoginRequest) (*apisv1.LoginResponse, error) {
+	var handler authHandler
+	var err error
 	loginType := sysInfo.LoginType
 
 	switch loginType {
@@ -166,6 +245,11 @@ func (a *authenticationUsecaseImpl) Login(ctx context.Context, loginReq apisv1.L
 		if err != nil {
 			return nil, err
 		}
+	case model.LoginTypeOIDC:
+		handler, err = a.newOIDCHandler(ctx, loginReq, sysInfo.OIDCConfig)
+		if err != nil {
+			return nil, err
+		}
 	case model.LoginTypeLocal:
 		handler, err = a.newLocalHandler(loginReq)
 		if err != nil {
@@ -273,6 +357,24 @@ func (a *authenticationUsecaseImpl) GetDexConfig(ctx context.Context) (*apisv1.D
 	}, nil
 }
 
+func (a *authenticationUsecaseImpl) GetOIDCConfig(ctx context.Context) (*apisv1.OIDCConfigBase, error) {
+	sysInfo, err := a.sysUsecase.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if sysInfo.OIDCConfig == nil {
+		return nil, bcode.ErrOIDCConfigNotFound
+	}
+	return convertOIDCConfigBase(sysInfo.OIDCConfig), nil
+}
+
+func getOIDCScopes(config *model.OIDCConfig) []string {
+	if len(config.Scopes) > 0 {
+		return config.Scopes
+	}
+	return []string{oidc.ScopeOpenID, "profile", "email"}
+}
+
 func generateDexConfig(ctx context.Context, kubeClient client.Client, update *model.UpdateDexConfig) error {
 	secret, err := initDexConfig(ctx, kubeClient, update.VelaAddress)
 	if err != nil {
@@ -313,6 +415,9 @@ func generateDexConfig(ctx context.Context, kubeClient client.Client, update *mo
 }
 
 func initDexConfig(ctx context.Context, kubeClient client.Client, velaAddress string) (*corev1.Secret, error) {
+	if velaAddress == "" {
+		velaAddress = defaultVelaAddress
+	}
 	dexConfig := model.DexConfig{
 		Issuer: fmt.Sprintf("%s/dex", velaAddress),
 		Web: model.DexWeb{
@@ -448,26 +553,75 @@ func (d *dexHandlerImpl) login(ctx context.Context) (*apisv1.UserBase, error) {
 	if err := d.idToken.Claims(&claims); err != nil {
 		return nil, err
 	}
+	return loginOrCreateUser(ctx, d.ds, d.notificationUsecase, claims.Email, claims.Name)
+}
 
-	user := &model.User{Email: claims.Email}
-	userBase := &apisv1.UserBase{Email: claims.Email, Name: claims.Name}
-	users, err := d.ds.List(ctx, user, &datastore.ListOptions{})
+func (o *oidcHandlerImpl) login(ctx context.Context) (*apisv1.UserBase, error) {
+	var claims map[string]interface{}
+	if err := o.idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+	emailClaim := o.emailClaim
+	if emailClaim == "" {
+		emailClaim = defaultOIDCEmailClaim
+	}
+	nameClaim := o.nameClaim
+	if nameClaim == "" {
+		nameClaim = defaultOIDCNameClaim
+	}
+	email, _ := claims[emailClaim].(string)
+	if email == "" {
+		return nil, bcode.ErrOIDCClaimMissing
+	}
+	// the user is matched by the email, so the unverified email could take over the existing user
+	if !o.allowUnverifiedEmail && isEmailUnverified(claims) {
+		return nil, bcode.ErrOIDCEmailNotVerified
+	}
+	name, _ := claims[nameClaim].(string)
+	if name == "" {
+		name = email
+	}
+	return loginOrCreateUser(ctx, o.ds, o.notificationUsecase, email, name)
+}
+
+// isEmailUnverified checks the email_verified claim, some providers set it as a string.
+// The email is treated as verified if the claim is not present.
+func isEmailUnverified(claims map[string]interface{}) bool {
+	switch verified := claims[oidcEmailVerifiedClaim].(type) {
+	case bool:
+		return !verified
+	case string:
+		return verified == "false"
+	}
+	return false
+}
+
+// loginOrCreateUser updates the login time of the user matched by the email,
+// the user will be created if there is no user with this email.
+func loginOrCreateUser(ctx context.Context, ds datastore.DataStore, notificationUsecase NotificationUsecase, email, name string) (*apisv1.UserBase, error) {
+	user := &model.User{Email: email}
+	userBase := &apisv1.UserBase{Email: email, Name: name}
+	users, err := ds.List(ctx, user, &datastore.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
 	if len(users) > 0 {
 		u := users[0].(*model.User)
 		u.LastLoginTime = time.Now()
-		if err := d.ds.Put(ctx, u); err != nil {
+		if err := ds.Put(ctx, u); err != nil {
 			return nil, err
 		}
 		userBase.Name = u.Name
-	} else if err := d.ds.Add(ctx, &model.User{
-		Email:         claims.Email,
-		Name:          claims.Name,
-		LastLoginTime: time.Now(),
-	}); err != nil {
-		return nil, err
+		userBase.Disabled = u.Disabled
+	} else {
+		if err := ds.Add(ctx, &model.User{
+			Email:         email,
+			Name:          name,
+			LastLoginTime: time.Now(),
+		}); err != nil {
+			return nil, err
+		}
+		emitNotification(ctx, notificationUsecase, model.EventUserCreated, map[string]string{"name": name, "email": email})
 	}
 
 	return userBase, nil
@@ -19,7 +19,10 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strconv"
 	"time"
@@ -40,6 +43,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/apiserver/model"
 	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
 	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
 )
 
@@ -200,4 +204,138 @@ var _ = Describe("Test authentication usecase functions", func() {
 		Expect(config.ClientSecret).Should(Equal("velaux-secret"))
 		Expect(config.RedirectURL).Should(Equal("http://velaux.com/callback"))
 	})
+
+	It("Test OIDC login", func() {
+		testIDToken := &oidc.IDToken{}
+		patch := ApplyMethod(reflect.TypeOf(testIDToken), "Claims", func(_ *oidc.IDToken, v interface{}) error {
+			return json.Unmarshal([]byte(`{"mail":"oidc@test.com","preferred_username":"oidc-user"}`), v)
+		})
+		defer patch.Reset()
+		oidcHandler := oidcHandlerImpl{
+			idToken:    testIDToken,
+			ds:         ds,
+			emailClaim: "mail",
+			nameClaim:  "preferred_username",
+		}
+		resp, err := oidcHandler.login(context.Background())
+		Expect(err).Should(BeNil())
+		Expect(resp.Email).Should(Equal("oidc@test.com"))
+		Expect(resp.Name).Should(Equal("oidc-user"))
+
+		user := &model.User{
+			Name: "oidc-user",
+		}
+		err = ds.Get(context.Background(), user)
+		Expect(err).Should(BeNil())
+		Expect(user.Email).Should(Equal("oidc@test.com"))
+
+		By("the default claims are missing in the id token")
+		oidcHandler.emailClaim = ""
+		_, err = oidcHandler.login(context.Background())
+		Expect(err).Should(Equal(bcode.ErrOIDCClaimMissing))
+	})
+
+	It("Test OIDC login with the unverified email", func() {
+		err := ds.Add(context.Background(), &model.User{Name: "unverified-admin", Email: "unverified@test.com"})
+		Expect(err).Should(BeNil())
+		testIDToken := &oidc.IDToken{}
+		patch := ApplyMethod(reflect.TypeOf(testIDToken), "Claims", func(_ *oidc.IDToken, v interface{}) error {
+			return json.Unmarshal([]byte(`{"email":"unverified@test.com","email_verified":false,"name":"attacker"}`), v)
+		})
+		defer patch.Reset()
+		oidcHandler := oidcHandlerImpl{
+			idToken: testIDToken,
+			ds:      ds,
+		}
+		_, err = oidcHandler.login(context.Background())
+		Expect(err).Should(Equal(bcode.ErrOIDCEmailNotVerified))
+
+		By("the unverified email is allowed by the config")
+		oidcHandler.allowUnverifiedEmail = true
+		resp, err := oidcHandler.login(context.Background())
+		Expect(err).Should(BeNil())
+		Expect(resp.Name).Should(Equal("unverified-admin"))
+	})
+
+	It("Test the oidc issuer with the trailing slash", func() {
+		var issuer string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/.well-known/openid-configuration" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"issuer":"%s","authorization_endpoint":"%sauth","token_endpoint":"%stoken","jwks_uri":"%skeys"}`, issuer, issuer, issuer, issuer)))
+		}))
+		defer server.Close()
+		issuer = server.URL + "/"
+
+		err := ds.Add(context.Background(), &model.User{Name: "admin", Email: "test@test.com"})
+		Expect(err).Should(BeNil())
+		resp, err := sysUsecase.UpdateSystemInfo(context.Background(), apisv1.SystemInfoRequest{
+			LoginType: model.LoginTypeOIDC,
+			OIDCConfig: &apisv1.OIDCConfigRequest{
+				Issuer:       issuer,
+				ClientID:     "velaux",
+				ClientSecret: "secret",
+				RedirectURL:  "http://velaux.com/callback",
+			},
+		})
+		Expect(err).Should(BeNil())
+		Expect(resp.OIDCConfig.Issuer).Should(Equal(issuer))
+		info, err := sysUsecase.Get(context.Background())
+		Expect(err).Should(BeNil())
+		Expect(info.OIDCConfig.Issuer).Should(Equal(issuer))
+	})
+
+	It("Test update system info with the oidc login type", func() {
+		var issuer string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"issuer":"%s","authorization_endpoint":"%s/auth","token_endpoint":"%s/token","jwks_uri":"%s/keys"}`, issuer, issuer, issuer, issuer)))
+		}))
+		defer server.Close()
+		issuer = server.URL
+
+		err := ds.Add(context.Background(), &model.User{Name: "admin", Email: "test@test.com"})
+		Expect(err).Should(BeNil())
+		_, err = sysUsecase.UpdateSystemInfo(context.Background(), apisv1.SystemInfoRequest{
+			LoginType: model.LoginTypeOIDC,
+		})
+		Expect(err).Should(Equal(bcode.ErrOIDCConfigNotFound))
+
+		resp, err := sysUsecase.UpdateSystemInfo(context.Background(), apisv1.SystemInfoRequest{
+			LoginType: model.LoginTypeOIDC,
+			OIDCConfig: &apisv1.OIDCConfigRequest{
+				Issuer:       issuer,
+				ClientID:     "velaux",
+				ClientSecret: "secret",
+				RedirectURL:  "http://velaux.com/callback",
+			},
+		})
+		Expect(err).Should(BeNil())
+		Expect(resp.LoginType).Should(Equal(model.LoginTypeOIDC))
+		Expect(resp.OIDCConfig.Issuer).Should(Equal(issuer))
+
+		By("the client secret should be kept when it is not set")
+		_, err = sysUsecase.UpdateSystemInfo(context.Background(), apisv1.SystemInfoRequest{
+			LoginType: model.LoginTypeOIDC,
+			OIDCConfig: &apisv1.OIDCConfigRequest{
+				Issuer:      issuer,
+				ClientID:    "velaux",
+				RedirectURL: "http://velaux.com/callback",
+				EmailClaim:  "mail",
+			},
+		})
+		Expect(err).Should(BeNil())
+		info, err := sysUsecase.Get(context.Background())
+		Expect(err).Should(BeNil())
+		Expect(info.OIDCConfig.ClientSecret).Should(Equal("secret"))
+		Expect(info.OIDCConfig.EmailClaim).Should(Equal("mail"))
+
+		authUsecase.sysUsecase = sysUsecase
+		config, err := authUsecase.GetOIDCConfig(context.Background())
+		Expect(err).Should(BeNil())
+		Expect(config.ClientID).Should(Equal("velaux"))
+	})
 })
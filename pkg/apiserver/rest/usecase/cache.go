@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/metrics"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils"
+)
+
+const (
+	cacheGroupSystemInfo = "systemInfo"
+	cacheGroupDefinition = "definition"
+	cacheGroupAddon      = "addon"
+)
+
+// Cache is the in-memory cache shared by the read-heavy usecases. The data is expired after the TTL,
+// and the usecases must invalidate the cached data after modifying it. The nil cache is disabled.
+type Cache struct {
+	store *utils.MemoryCacheStore
+}
+
+// NewCache new usecase cache, the nil cache is returned if it is disabled
+func NewCache(ctx context.Context, enabled bool) *Cache {
+	if !enabled {
+		return nil
+	}
+	return &Cache{store: utils.NewMemoryCacheStore(ctx)}
+}
+
+// Get returns the cached data of the group, the second return value is false if the data is not cached or expired
+func (c *Cache) Get(group, key string) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	if data := c.store.Get(cacheKey(group, key)); data != nil {
+		metrics.CacheRequestCounter.WithLabelValues(group, metrics.CacheResultHit).Inc()
+		return data, true
+	}
+	metrics.CacheRequestCounter.WithLabelValues(group, metrics.CacheResultMiss).Inc()
+	return nil, false
+}
+
+// Put caches the data of the group, the nil data is not cached
+func (c *Cache) Put(group, key string, data interface{}, ttl time.Duration) {
+	if c == nil || data == nil {
+		return
+	}
+	c.store.Put(cacheKey(group, key), data, ttl)
+}
+
+// Invalidate removes the cached data of the keys, all data of the group is removed if there is no key
+func (c *Cache) Invalidate(group string, keys ...string) {
+	if c == nil {
+		return
+	}
+	if len(keys) == 0 {
+		c.store.DeleteByPrefix(group + "/")
+		return
+	}
+	for _, key := range keys {
+		c.store.Delete(cacheKey(group, key))
+	}
+}
+
+func cacheKey(group, key string) string {
+	return group + "/" + key
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/metrics"
+)
+
+func TestCache(t *testing.T) {
+	cache := NewCache(context.TODO(), true)
+	hits := testutil.ToFloat64(metrics.CacheRequestCounter.WithLabelValues(cacheGroupDefinition, metrics.CacheResultHit))
+	misses := testutil.ToFloat64(metrics.CacheRequestCounter.WithLabelValues(cacheGroupDefinition, metrics.CacheResultMiss))
+
+	_, ok := cache.Get(cacheGroupDefinition, "component")
+	assert.False(t, ok)
+	cache.Put(cacheGroupDefinition, "component", "component data", time.Minute)
+	cache.Put(cacheGroupDefinition, "trait", "trait data", time.Minute)
+	cache.Put(cacheGroupAddon, "registries", "registries data", time.Minute)
+	data, ok := cache.Get(cacheGroupDefinition, "component")
+	assert.True(t, ok)
+	assert.Equal(t, "component data", data)
+	assert.Equal(t, hits+1, testutil.ToFloat64(metrics.CacheRequestCounter.WithLabelValues(cacheGroupDefinition, metrics.CacheResultHit)))
+	assert.Equal(t, misses+1, testutil.ToFloat64(metrics.CacheRequestCounter.WithLabelValues(cacheGroupDefinition, metrics.CacheResultMiss)))
+
+	cache.Invalidate(cacheGroupDefinition, "component")
+	_, ok = cache.Get(cacheGroupDefinition, "component")
+	assert.False(t, ok)
+	_, ok = cache.Get(cacheGroupDefinition, "trait")
+	assert.True(t, ok)
+
+	cache.Invalidate(cacheGroupDefinition)
+	_, ok = cache.Get(cacheGroupDefinition, "trait")
+	assert.False(t, ok)
+	_, ok = cache.Get(cacheGroupAddon, "registries")
+	assert.True(t, ok)
+
+	// the disabled cache never caches the data
+	var disabled = NewCache(context.TODO(), false)
+	disabled.Put(cacheGroupAddon, "registries", "registries data", time.Minute)
+	_, ok = disabled.Get(cacheGroupAddon, "registries")
+	assert.False(t, ok)
+	disabled.Invalidate(cacheGroupAddon)
+}
@@ -17,9 +17,13 @@
 package usecase
 
 import (
+	"strconv"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
 	"github.com/oam-dev/kubevela/pkg/apiserver/log"
 	"github.com/oam-dev/kubevela/pkg/apiserver/model"
 	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils"
 	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
 )
 
@@ -76,3 +80,35 @@ func convertAPIStep2ModelStep(apiSteps []apisv1.WorkflowStep) ([]model.WorkflowS
 	}
 	return steps, nil
 }
+
+// convertSortOptions converts the sort options of the request to the datastore options, the default options are used if the request sets none
+func convertSortOptions(sortBy []apisv1.SortOption, defaults ...datastore.SortOption) []datastore.SortOption {
+	if len(sortBy) == 0 {
+		return defaults
+	}
+	var options []datastore.SortOption
+	for _, op := range sortBy {
+		order := datastore.SortOrderAscending
+		if op.Descending {
+			order = datastore.SortOrderDescending
+		}
+		options = append(options, datastore.SortOption{Key: op.Key, Order: order})
+	}
+	return options
+}
+
+// convertEqualFilters converts the filters of the request to the datastore equal query options,
+// the values of the boolean keys are parsed as the booleans, so they match the boolean fields.
+func convertEqualFilters(filters []apisv1.EqualFilter, boolKeys ...string) []datastore.EqualQueryOption {
+	var equals []datastore.EqualQueryOption
+	for _, filter := range filters {
+		var value interface{} = filter.Value
+		if utils.StringsContain(boolKeys, filter.Key) {
+			if b, err := strconv.ParseBool(filter.Value); err == nil {
+				value = b
+			}
+		}
+		equals = append(equals, datastore.EqualQueryOption{Key: filter.Key, Value: value})
+	}
+	return equals
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -45,7 +46,7 @@ import (
 // DefinitionUsecase definition usecase, Implement the management of ComponentDefinition、TraitDefinition and WorkflowStepDefinition.
 type DefinitionUsecase interface {
 	// ListDefinitions list definition base info
-	ListDefinitions(ctx context.Context, ops DefinitionQueryOption) ([]*apisv1.DefinitionBase, error)
+	ListDefinitions(ctx context.Context, ops DefinitionQueryOption) (*apisv1.ListDefinitionResponse, error)
 	// DetailDefinition get definition detail
 	DetailDefinition(ctx context.Context, name, defType string) (*apisv1.DetailDefinitionResponse, error)
 	// AddDefinitionUISchema add or update custom definition ui schema
@@ -56,7 +57,7 @@ type DefinitionUsecase interface {
 
 type definitionUsecaseImpl struct {
 	kubeClient client.Client
-	caches     *utils.MemoryCacheStore
+	cache      *Cache
 }
 
 // DefinitionQueryOption define a set of query options
@@ -64,6 +65,12 @@ type DefinitionQueryOption struct {
 	Type             string `json:"type"`
 	AppliedWorkloads string `json:"appliedWorkloads"`
 	QueryAll         bool   `json:"queryAll"`
+	// Query searches the name, alias and description of the definitions
+	Query    string              `json:"query"`
+	Status   string              `json:"status"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"pageSize"`
+	SortBy   []apisv1.SortOption `json:"sortBy"`
 }
 
 // String return cache key string
@@ -80,15 +87,15 @@ const (
 )
 
 // NewDefinitionUsecase new definition usecase
-func NewDefinitionUsecase() DefinitionUsecase {
+func NewDefinitionUsecase(cache *Cache) DefinitionUsecase {
 	kubecli, err := clients.GetKubeClient()
 	if err != nil {
 		log.Logger.Fatalf("get kubeclient failure %s", err.Error())
 	}
-	return &definitionUsecaseImpl{kubeClient: kubecli, caches: utils.NewMemoryCacheStore(context.Background())}
+	return &definitionUsecaseImpl{kubeClient: kubecli, cache: cache}
 }
 
-func (d *definitionUsecaseImpl) ListDefinitions(ctx context.Context, ops DefinitionQueryOption) ([]*apisv1.DefinitionBase, error) {
+func (d *definitionUsecaseImpl) ListDefinitions(ctx context.Context, ops DefinitionQueryOption) (*apisv1.ListDefinitionResponse, error) {
 	defs := &unstructured.UnstructuredList{}
 	version, kind, err := getKindAndVersion(ops.Type)
 	if err != nil {
@@ -96,12 +103,73 @@ func (d *definitionUsecaseImpl) ListDefinitions(ctx context.Context, ops Definit
 	}
 	defs.SetAPIVersion(version)
 	defs.SetKind(kind)
-	return d.listDefinitions(ctx, defs, kind, ops)
+	list, err := d.listDefinitions(ctx, defs, kind, ops)
+	if err != nil {
+		return nil, err
+	}
+	return filterDefinitions(list, ops), nil
+}
+
+// filterDefinitions filters, sorts and pages the definitions, the cached list is not modified.
+func filterDefinitions(defs []*apisv1.DefinitionBase, ops DefinitionQueryOption) *apisv1.ListDefinitionResponse {
+	query := strings.ToLower(ops.Query)
+	var list []*apisv1.DefinitionBase
+	for _, def := range defs {
+		if ops.Status != "" && def.Status != ops.Status {
+			continue
+		}
+		if query != "" &&
+			!(strings.Contains(strings.ToLower(def.Name), query) ||
+				strings.Contains(strings.ToLower(def.Alias), query) ||
+				strings.Contains(strings.ToLower(def.Description), query)) {
+			continue
+		}
+		list = append(list, def)
+	}
+	if len(ops.SortBy) > 0 {
+		sort.SliceStable(list, func(i, j int) bool {
+			for _, op := range ops.SortBy {
+				x, y := definitionSortValue(list[i], op.Key), definitionSortValue(list[j], op.Key)
+				if x == y {
+					continue
+				}
+				if op.Descending {
+					return x > y
+				}
+				return x < y
+			}
+			return false
+		})
+	}
+	total := int64(len(list))
+	if ops.Page > 0 && ops.PageSize > 0 {
+		skip := (ops.Page - 1) * ops.PageSize
+		if skip >= len(list) {
+			list = []*apisv1.DefinitionBase{}
+		} else {
+			list = list[skip:]
+		}
+		if len(list) > ops.PageSize {
+			list = list[:ops.PageSize]
+		}
+	}
+	return &apisv1.ListDefinitionResponse{Definitions: list, Total: total}
+}
+
+func definitionSortValue(def *apisv1.DefinitionBase, key string) string {
+	switch key {
+	case "alias":
+		return def.Alias
+	case "status":
+		return def.Status
+	default:
+		return def.Name
+	}
 }
 
 func (d *definitionUsecaseImpl) listDefinitions(ctx context.Context, list *unstructured.UnstructuredList, kind string, ops DefinitionQueryOption) ([]*apisv1.DefinitionBase, error) {
-	if mc := d.caches.Get(ops.String()); mc != nil {
-		return mc.([]*apisv1.DefinitionBase), nil
+	if data, ok := d.cache.Get(cacheGroupDefinition, ops.String()); ok {
+		return data.([]*apisv1.DefinitionBase), nil
 	}
 	matchLabels := metav1.LabelSelector{
 		MatchExpressions: []metav1.LabelSelectorRequirement{
@@ -152,7 +220,7 @@ func (d *definitionUsecaseImpl) listDefinitions(ctx context.Context, list *unstr
 		defs = append(defs, definition)
 	}
 	if ops.AppliedWorkloads == "" {
-		d.caches.Put(ops.String(), defs, time.Minute*3)
+		d.cache.Put(cacheGroupDefinition, ops.String(), defs, time.Minute*3)
 	}
 	return defs, nil
 }
@@ -357,6 +425,7 @@ func (d *definitionUsecaseImpl) UpdateDefinitionStatus(ctx context.Context, name
 		if err := d.kubeClient.Update(ctx, def); err != nil {
 			return nil, err
 		}
+		d.cache.Invalidate(cacheGroupDefinition)
 	}
 	if !exist && update.HiddenInUI {
 		labels := def.GetLabels()
@@ -365,6 +434,7 @@ func (d *definitionUsecaseImpl) UpdateDefinitionStatus(ctx context.Context, name
 		if err := d.kubeClient.Update(ctx, def); err != nil {
 			return nil, err
 		}
+		d.cache.Invalidate(cacheGroupDefinition)
 	}
 	return d.DetailDefinition(ctx, name, update.DefinitionType)
 }
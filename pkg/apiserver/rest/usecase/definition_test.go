@@ -44,7 +44,7 @@ var _ = Describe("Test namespace usecase functions", func() {
 	)
 
 	BeforeEach(func() {
-		definitionUsecase = &definitionUsecaseImpl{kubeClient: k8sClient, caches: utils.NewMemoryCacheStore(context.TODO())}
+		definitionUsecase = &definitionUsecaseImpl{kubeClient: k8sClient, cache: NewCache(context.TODO(), true)}
 		err := k8sClient.Create(context.Background(), &corev1.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: "vela-system",
@@ -62,8 +62,9 @@ var _ = Describe("Test namespace usecase functions", func() {
 		err = k8sClient.Create(context.Background(), &cd)
 		Expect(err).Should(Succeed())
 
-		definitions, err := definitionUsecase.ListDefinitions(context.TODO(), DefinitionQueryOption{Type: "component"})
+		componentList, err := definitionUsecase.ListDefinitions(context.TODO(), DefinitionQueryOption{Type: "component"})
 		Expect(err).Should(BeNil())
+		definitions := componentList.Definitions
 		var selectDefinition *v1.DefinitionBase
 		for i, definition := range definitions {
 			if definition.WorkloadType == "deployments.apps" {
@@ -83,8 +84,9 @@ var _ = Describe("Test namespace usecase functions", func() {
 		Expect(err).Should(Succeed())
 		err = k8sClient.Create(context.Background(), &td)
 		Expect(err).Should(Succeed())
-		traits, err := definitionUsecase.ListDefinitions(context.TODO(), DefinitionQueryOption{Type: "trait"})
+		traitList, err := definitionUsecase.ListDefinitions(context.TODO(), DefinitionQueryOption{Type: "trait"})
 		Expect(err).Should(BeNil())
+		traits := traitList.Definitions
 		// there is already a scaler trait definition in the test env
 		Expect(cmp.Diff(len(traits), 2)).Should(BeEmpty())
 		Expect(cmp.Diff(traits[0].Name, "myingress")).Should(BeEmpty())
@@ -101,8 +103,9 @@ var _ = Describe("Test namespace usecase functions", func() {
 		err = k8sClient.Create(context.Background(), &sd)
 		Expect(err).Should(Succeed())
 
-		wfstep, err := definitionUsecase.ListDefinitions(context.TODO(), DefinitionQueryOption{Type: "workflowstep"})
+		stepList, err := definitionUsecase.ListDefinitions(context.TODO(), DefinitionQueryOption{Type: "workflowstep"})
 		Expect(err).Should(BeNil())
+		wfstep := stepList.Definitions
 		// there is already a deploy workflow step definition in the test env
 		Expect(cmp.Diff(len(wfstep), 2)).Should(BeEmpty())
 		Expect(cmp.Diff(wfstep[0].Name, "apply-application")).Should(BeEmpty())
@@ -120,7 +123,8 @@ var _ = Describe("Test namespace usecase functions", func() {
 
 		allstep, err := definitionUsecase.ListDefinitions(context.TODO(), DefinitionQueryOption{Type: "workflowstep", QueryAll: true})
 		Expect(err).Should(BeNil())
-		Expect(cmp.Diff(len(allstep), 3)).Should(BeEmpty())
+		Expect(cmp.Diff(len(allstep.Definitions), 3)).Should(BeEmpty())
+		Expect(cmp.Diff(allstep.Total, int64(3))).Should(BeEmpty())
 
 		By("List policy definitions")
 		var policy = v1beta1.PolicyDefinition{
@@ -138,8 +142,9 @@ var _ = Describe("Test namespace usecase functions", func() {
 		}
 		err = k8sClient.Create(context.Background(), &policy)
 		Expect(err).Should(Succeed())
-		policies, err := definitionUsecase.ListDefinitions(context.TODO(), DefinitionQueryOption{Type: "policy"})
+		policyList, err := definitionUsecase.ListDefinitions(context.TODO(), DefinitionQueryOption{Type: "policy"})
 		Expect(err).Should(BeNil())
+		policies := policyList.Definitions
 		Expect(cmp.Diff(len(policies), 1)).Should(BeEmpty())
 		Expect(cmp.Diff(policies[0].Name, "health")).Should(BeEmpty())
 		Expect(policies[0].Description).ShouldNot(BeEmpty())
@@ -213,7 +218,7 @@ var _ = Describe("Test namespace usecase functions", func() {
 	It("Test sortDefaultUISchema", testSortDefaultUISchema)
 
 	It("Test update ui schema", func() {
-		du := NewDefinitionUsecase()
+		du := NewDefinitionUsecase(nil)
 		cdata, err := ioutil.ReadFile("./testdata/workflowstep-apply-object.yaml")
 		Expect(err).Should(Succeed())
 		var schema utils.UISchema
@@ -230,7 +235,7 @@ var _ = Describe("Test namespace usecase functions", func() {
 	})
 
 	It("Test update status of the definition", func() {
-		du := NewDefinitionUsecase()
+		du := NewDefinitionUsecase(nil)
 		detail, err := du.UpdateDefinitionStatus(context.TODO(), "apply-object", v1.UpdateDefinitionStatusRequest{
 			DefinitionType: "workflowstep",
 			HiddenInUI:     true,
@@ -378,3 +383,27 @@ func TestDefinitionQueryOption(t *testing.T) {
 		QueryAll: true,
 	}.String(), false)
 }
+
+func TestFilterDefinitions(t *testing.T) {
+	defs := []*v1.DefinitionBase{
+		{Name: "webservice", Alias: "Web Service", Status: "enable"},
+		{Name: "worker", Description: "backend worker", Status: "enable"},
+		{Name: "task", Status: "disable"},
+		{Name: "cron-task", Alias: "Cron Worker", Status: "enable"},
+	}
+	resp := filterDefinitions(defs, DefinitionQueryOption{Query: "WORKER", Status: "enable"})
+	assert.Equal(t, int64(2), resp.Total)
+	assert.Equal(t, "worker", resp.Definitions[0].Name)
+	assert.Equal(t, "cron-task", resp.Definitions[1].Name)
+
+	resp = filterDefinitions(defs, DefinitionQueryOption{
+		SortBy:   []v1.SortOption{{Key: "name", Descending: true}},
+		Page:     2,
+		PageSize: 3,
+	})
+	assert.Equal(t, int64(4), resp.Total)
+	assert.Equal(t, 1, len(resp.Definitions))
+	assert.Equal(t, "cron-task", resp.Definitions[0].Name)
+	// the cached list is not sorted in place
+	assert.Equal(t, "webservice", defs[0].Name)
+}
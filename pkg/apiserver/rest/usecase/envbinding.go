@@ -61,25 +61,27 @@ type EnvBindingUsecase interface {
 }
 
 type envBindingUsecaseImpl struct {
-	ds                datastore.DataStore
-	workflowUsecase   WorkflowUsecase
-	envUsecase        EnvUsecase
-	definitionUsecase DefinitionUsecase
-	kubeClient        client.Client
+	ds                  datastore.DataStore
+	workflowUsecase     WorkflowUsecase
+	envUsecase          EnvUsecase
+	definitionUsecase   DefinitionUsecase
+	notificationUsecase NotificationUsecase
+	kubeClient          client.Client
 }
 
 // NewEnvBindingUsecase new envBinding usecase
-func NewEnvBindingUsecase(ds datastore.DataStore, workflowUsecase WorkflowUsecase, definitionUsecase DefinitionUsecase, envUsecase EnvUsecase) EnvBindingUsecase {
+func NewEnvBindingUsecase(ds datastore.DataStore, workflowUsecase WorkflowUsecase, definitionUsecase DefinitionUsecase, envUsecase EnvUsecase, notificationUsecase NotificationUsecase) EnvBindingUsecase {
 	kubecli, err := clients.GetKubeClient()
 	if err != nil {
 		log.Logger.Fatalf("get kubeclient failure %s", err.Error())
 	}
 	return &envBindingUsecaseImpl{
-		ds:                ds,
-		workflowUsecase:   workflowUsecase,
-		definitionUsecase: definitionUsecase,
-		kubeClient:        kubecli,
-		envUsecase:        envUsecase,
+		ds:                  ds,
+		workflowUsecase:     workflowUsecase,
+		definitionUsecase:   definitionUsecase,
+		kubeClient:          kubecli,
+		envUsecase:          envUsecase,
+		notificationUsecase: notificationUsecase,
 	}
 }
 
@@ -361,7 +363,7 @@ func (e *envBindingUsecaseImpl) ApplicationEnvRecycle(ctx context.Context, appMo
 		return err
 	}
 
-	if err := resetRevisionsAndRecords(ctx, e.ds, appModel.Name, "", "", ""); err != nil {
+	if err := resetRevisionsAndRecords(ctx, e.ds, e.notificationUsecase, appModel.Name, "", "", ""); err != nil {
 		return err
 	}
 	return nil
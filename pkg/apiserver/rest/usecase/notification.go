@@ -0,0 +1,440 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/log"
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
+)
+
+const (
+	// NotificationEventHeader is the header carrying the event name
+	NotificationEventHeader = "X-Vela-Event"
+	// NotificationDeliveryHeader is the header carrying the delivery ID
+	NotificationDeliveryHeader = "X-Vela-Delivery"
+	// NotificationSignatureHeader is the header carrying the HMAC-SHA256 signature of the payload
+	NotificationSignatureHeader = "X-Vela-Signature"
+
+	// maxDeliveryHistory is the number of the delivery records kept for each webhook
+	maxDeliveryHistory = 100
+	// notificationQueueSize is the number of the events waiting for the delivery, the new events are dropped when it is full
+	notificationQueueSize = 100
+	// notificationWorkers is the number of the workers delivering the events
+	notificationWorkers = 4
+)
+
+// the first retry is after 2 seconds, at most 4 attempts
+var notificationBackoff = wait.Backoff{
+	Steps:    4,
+	Duration: 2 * time.Second,
+	Factor:   3.0,
+	Jitter:   0.1,
+}
+
+// NotificationUsecase manage the notification webhooks and deliver the platform events to them
+type NotificationUsecase interface {
+	CreateWebhook(ctx context.Context, req apisv1.CreateNotificationWebhookRequest) (*apisv1.NotificationWebhookBase, error)
+	UpdateWebhook(ctx context.Context, name string, req apisv1.UpdateNotificationWebhookRequest) (*apisv1.NotificationWebhookBase, error)
+	DeleteWebhook(ctx context.Context, name string) error
+	ListWebhooks(ctx context.Context) (*apisv1.ListNotificationWebhookResponse, error)
+	ListDeliveries(ctx context.Context, name string, page, pageSize int) (*apisv1.ListNotificationDeliveryResponse, error)
+	TestWebhook(ctx context.Context, name string) (*apisv1.NotificationDeliveryBase, error)
+	// Emit delivers the event to the subscribed webhooks in the background
+	Emit(ctx context.Context, event string, data map[string]string)
+}
+
+type notificationUsecaseImpl struct {
+	ds         datastore.DataStore
+	httpClient *http.Client
+	backoff    wait.Backoff
+	queue      chan apisv1.NotificationPayload
+}
+
+// NewNotificationUsecase new notification usecase, the events are delivered by the workers until the context is done
+func NewNotificationUsecase(ctx context.Context, ds datastore.DataStore) NotificationUsecase {
+	n := &notificationUsecaseImpl{
+		ds:         ds,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		backoff:    notificationBackoff,
+		queue:      make(chan apisv1.NotificationPayload, notificationQueueSize),
+	}
+	n.start(ctx, notificationWorkers)
+	return n
+}
+
+// start starts the workers delivering the events in the queue
+func (n *notificationUsecaseImpl) start(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case payload := <-n.queue:
+					n.dispatch(ctx, payload)
+				}
+			}
+		}()
+	}
+}
+
+// emitNotification emits the event if the notification usecase is set
+func emitNotification(ctx context.Context, n NotificationUsecase, event string, data map[string]string) {
+	if n != nil {
+		n.Emit(ctx, event, data)
+	}
+}
+
+// SignNotificationPayload returns the hex encoded HMAC-SHA256 signature of the payload
+func SignNotificationPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateWebhook create a notification webhook
+func (n *notificationUsecaseImpl) CreateWebhook(ctx context.Context, req apisv1.CreateNotificationWebhookRequest) (*apisv1.NotificationWebhookBase, error) {
+	if err := checkNotificationEvents(req.Events); err != nil {
+		return nil, err
+	}
+	webhook := &model.NotificationWebhook{
+		Name:        req.Name,
+		Alias:       req.Alias,
+		Description: req.Description,
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      req.Events,
+		Disabled:    req.Disabled,
+	}
+	if err := n.ds.Add(ctx, webhook); err != nil {
+		if errors.Is(err, datastore.ErrRecordExist) {
+			return nil, bcode.ErrNotificationWebhookExist
+		}
+		return nil, err
+	}
+	return convertNotificationWebhookBase(webhook), nil
+}
+
+// UpdateWebhook update a notification webhook
+func (n *notificationUsecaseImpl) UpdateWebhook(ctx context.Context, name string, req apisv1.UpdateNotificationWebhookRequest) (*apisv1.NotificationWebhookBase, error) {
+	if err := checkNotificationEvents(req.Events); err != nil {
+		return nil, err
+	}
+	webhook, err := n.getWebhook(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Alias = req.Alias
+	webhook.Description = req.Description
+	webhook.URL = req.URL
+	webhook.Events = req.Events
+	webhook.Disabled = req.Disabled
+	if req.Secret != nil {
+		webhook.Secret = *req.Secret
+	}
+	if err := n.ds.Put(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return convertNotificationWebhookBase(webhook), nil
+}
+
+// DeleteWebhook delete a notification webhook and its delivery history
+func (n *notificationUsecaseImpl) DeleteWebhook(ctx context.Context, name string) error {
+	if err := n.ds.Delete(ctx, &model.NotificationWebhook{Name: name}); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return bcode.ErrNotificationWebhookNotExist
+		}
+		return err
+	}
+	deliveries, err := n.ds.List(ctx, &model.NotificationDelivery{Webhook: name}, &datastore.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		if err := n.ds.Delete(ctx, delivery); err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListWebhooks list all notification webhooks
+func (n *notificationUsecaseImpl) ListWebhooks(ctx context.Context) (*apisv1.ListNotificationWebhookResponse, error) {
+	entities, err := n.ds.List(ctx, &model.NotificationWebhook{}, &datastore.ListOptions{
+		SortBy: []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &apisv1.ListNotificationWebhookResponse{
+		Webhooks: []*apisv1.NotificationWebhookBase{},
+		Events:   model.NotificationEvents,
+	}
+	for _, entity := range entities {
+		resp.Webhooks = append(resp.Webhooks, convertNotificationWebhookBase(entity.(*model.NotificationWebhook)))
+	}
+	return resp, nil
+}
+
+// ListDeliveries list the delivery history of a notification webhook
+func (n *notificationUsecaseImpl) ListDeliveries(ctx context.Context, name string, page, pageSize int) (*apisv1.ListNotificationDeliveryResponse, error) {
+	if _, err := n.getWebhook(ctx, name); err != nil {
+		return nil, err
+	}
+	delivery := &model.NotificationDelivery{Webhook: name}
+	entities, err := n.ds.List(ctx, delivery, &datastore.ListOptions{
+		Page:     page,
+		PageSize: pageSize,
+		SortBy:   []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	count, err := n.ds.Count(ctx, delivery, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apisv1.ListNotificationDeliveryResponse{
+		Deliveries: []*apisv1.NotificationDeliveryBase{},
+		Total:      count,
+	}
+	for _, entity := range entities {
+		resp.Deliveries = append(resp.Deliveries, convertNotificationDeliveryBase(entity.(*model.NotificationDelivery)))
+	}
+	return resp, nil
+}
+
+// TestWebhook send a test event to the webhook without retrying, the disabled webhook could also be tested
+func (n *notificationUsecaseImpl) TestWebhook(ctx context.Context, name string) (*apisv1.NotificationDeliveryBase, error) {
+	webhook, err := n.getWebhook(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var operator string
+	if userName, ok := ctx.Value(&apisv1.CtxKeyUser).(string); ok {
+		operator = userName
+	}
+	payload := newNotificationPayload(model.EventNotificationTest, map[string]string{"webhook": name, "operator": operator})
+	delivery := n.deliver(ctx, webhook, payload, wait.Backoff{Steps: 1})
+	return convertNotificationDeliveryBase(delivery), nil
+}
+
+// Emit delivers the event to the enabled webhooks which subscribe the event
+func (n *notificationUsecaseImpl) Emit(ctx context.Context, event string, data map[string]string) {
+	select {
+	case n.queue <- newNotificationPayload(event, data):
+	default:
+		log.Logger.Warnf("the notification queue is full, the event %s is dropped", event)
+	}
+}
+
+// dispatch delivers the event to the subscribed webhooks one by one
+func (n *notificationUsecaseImpl) dispatch(ctx context.Context, payload apisv1.NotificationPayload) {
+	entities, err := n.ds.List(ctx, &model.NotificationWebhook{}, &datastore.ListOptions{})
+	if err != nil {
+		log.Logger.Errorf("failed to list the notification webhooks for the event %s: %s", payload.Event, err.Error())
+		return
+	}
+	for _, entity := range entities {
+		webhook := entity.(*model.NotificationWebhook)
+		if webhook.Disabled || !webhook.Subscribed(payload.Event) {
+			continue
+		}
+		n.deliver(ctx, webhook, payload, n.backoff)
+	}
+}
+
+func (n *notificationUsecaseImpl) getWebhook(ctx context.Context, name string) (*model.NotificationWebhook, error) {
+	webhook := &model.NotificationWebhook{Name: name}
+	if err := n.ds.Get(ctx, webhook); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrNotificationWebhookNotExist
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// deliver posts the payload to the webhook with retries and records the result
+func (n *notificationUsecaseImpl) deliver(ctx context.Context, webhook *model.NotificationWebhook, payload apisv1.NotificationPayload, backoff wait.Backoff) *model.NotificationDelivery {
+	delivery := &model.NotificationDelivery{
+		ID:      utilrand.String(16),
+		Webhook: webhook.Name,
+		Event:   payload.Event,
+	}
+	body, err := json.Marshal(payload)
+	if err == nil {
+		delivery.Payload = string(body)
+		// the retries are stopped once the context is canceled, e.g. the server is shutting down
+		var lastErr error
+		err = wait.ExponentialBackoffWithContext(ctx, backoff, func() (bool, error) {
+			delivery.Attempts++
+			statusCode, err := n.post(ctx, webhook, delivery.ID, payload.Event, body)
+			delivery.StatusCode = statusCode
+			if err != nil && isRetryableDeliveryError(err) {
+				lastErr = err
+				return false, nil
+			}
+			return err == nil, err
+		})
+		if errors.Is(err, wait.ErrWaitTimeout) {
+			err = lastErr
+		}
+	}
+	if err != nil {
+		delivery.Status = model.DeliveryStatusFailure
+		delivery.Error = err.Error()
+		log.Logger.Warnf("failed to deliver the event %s to the webhook %s: %s", payload.Event, webhook.Name, err.Error())
+	} else {
+		delivery.Status = model.DeliveryStatusSuccess
+	}
+	if err := n.ds.Add(ctx, delivery); err != nil {
+		log.Logger.Errorf("failed to save the delivery of the webhook %s: %s", webhook.Name, err.Error())
+		return delivery
+	}
+	n.pruneDeliveries(ctx, webhook.Name)
+	return delivery
+}
+
+func (n *notificationUsecaseImpl) post(ctx context.Context, webhook *model.NotificationWebhook, deliveryID, event string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(NotificationEventHeader, event)
+	req.Header.Set(NotificationDeliveryHeader, deliveryID)
+	if webhook.Secret != "" {
+		req.Header.Set(NotificationSignatureHeader, "sha256="+SignNotificationPayload(webhook.Secret, body))
+	}
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, &deliveryStatusError{statusCode: resp.StatusCode}
+	}
+	return resp.StatusCode, nil
+}
+
+// pruneDeliveries removes the oldest delivery records beyond the history limit
+func (n *notificationUsecaseImpl) pruneDeliveries(ctx context.Context, name string) {
+	delivery := &model.NotificationDelivery{Webhook: name}
+	count, err := n.ds.Count(ctx, delivery, nil)
+	if err != nil || count <= maxDeliveryHistory {
+		return
+	}
+	entities, err := n.ds.List(ctx, delivery, &datastore.ListOptions{
+		SortBy: []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderAscending}},
+	})
+	if err != nil {
+		log.Logger.Warnf("failed to list the deliveries of the webhook %s: %s", name, err.Error())
+		return
+	}
+	for i := 0; i < len(entities)-maxDeliveryHistory; i++ {
+		if err := n.ds.Delete(ctx, entities[i]); err != nil && !errors.Is(err, datastore.ErrRecordNotExist) {
+			log.Logger.Warnf("failed to delete the delivery of the webhook %s: %s", name, err.Error())
+		}
+	}
+}
+
+type deliveryStatusError struct {
+	statusCode int
+}
+
+func (e *deliveryStatusError) Error() string {
+	return fmt.Sprintf("the webhook responds with the status code %d", e.statusCode)
+}
+
+// isRetryableDeliveryError the client errors except too many requests are not retried
+func isRetryableDeliveryError(err error) bool {
+	var statusErr *deliveryStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.statusCode >= 500 || statusErr.statusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+func checkNotificationEvents(events []string) error {
+	for _, event := range events {
+		if !utils.StringsContain(model.NotificationEvents, event) {
+			return bcode.ErrInvalidNotificationEvent.SetMessage(fmt.Sprintf("the event %s is not supported", event))
+		}
+	}
+	return nil
+}
+
+func newNotificationPayload(event string, data map[string]string) apisv1.NotificationPayload {
+	return apisv1.NotificationPayload{
+		ID:        utilrand.String(16),
+		Event:     event,
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+}
+
+func convertNotificationWebhookBase(webhook *model.NotificationWebhook) *apisv1.NotificationWebhookBase {
+	return &apisv1.NotificationWebhookBase{
+		Name:        webhook.Name,
+		Alias:       webhook.Alias,
+		Description: webhook.Description,
+		URL:         webhook.URL,
+		HasSecret:   webhook.Secret != "",
+		Events:      webhook.Events,
+		Disabled:    webhook.Disabled,
+		CreateTime:  webhook.CreateTime,
+		UpdateTime:  webhook.UpdateTime,
+	}
+}
+
+func convertNotificationDeliveryBase(delivery *model.NotificationDelivery) *apisv1.NotificationDeliveryBase {
+	return &apisv1.NotificationDeliveryBase{
+		ID:         delivery.ID,
+		Webhook:    delivery.Webhook,
+		Event:      delivery.Event,
+		Payload:    delivery.Payload,
+		Status:     delivery.Status,
+		StatusCode: delivery.StatusCode,
+		Attempts:   delivery.Attempts,
+		Error:      delivery.Error,
+		CreateTime: delivery.CreateTime,
+	}
+}
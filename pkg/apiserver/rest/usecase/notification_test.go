@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
+)
+
+var _ = Describe("Test notification usecase functions", func() {
+	var (
+		notificationUsecase *notificationUsecaseImpl
+		ds                  datastore.DataStore
+		server              *httptest.Server
+		requests            int32
+		failures            int32
+		signatureValid      atomic.Value
+		cancel              context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		var err error
+		ds, err = NewDatastore(datastore.Config{Type: "kubeapi", Database: "notification-test-" + strconv.FormatInt(time.Now().UnixNano(), 10)})
+		Expect(ds).ToNot(BeNil())
+		Expect(err).Should(BeNil())
+		notificationUsecase = &notificationUsecaseImpl{
+			ds:         ds,
+			httpClient: http.DefaultClient,
+			backoff:    wait.Backoff{Steps: 3, Duration: 10 * time.Millisecond},
+			queue:      make(chan apisv1.NotificationPayload, notificationQueueSize),
+		}
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		notificationUsecase.start(ctx, 1)
+		atomic.StoreInt32(&requests, 0)
+		atomic.StoreInt32(&failures, 0)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			body, _ := ioutil.ReadAll(r.Body)
+			if signature := r.Header.Get(NotificationSignatureHeader); signature != "" {
+				signatureValid.Store(signature == "sha256="+SignNotificationPayload("secret", body))
+			}
+			if atomic.AddInt32(&failures, -1) >= 0 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+	})
+
+	AfterEach(func() {
+		cancel()
+		server.Close()
+	})
+
+	It("Test create, update and delete the notification webhook", func() {
+		_, err := notificationUsecase.CreateWebhook(context.TODO(), apisv1.CreateNotificationWebhookRequest{
+			Name:   "invalid-event",
+			URL:    server.URL,
+			Events: []string{"unknown"},
+		})
+		Expect(err).ShouldNot(BeNil())
+
+		webhook, err := notificationUsecase.CreateWebhook(context.TODO(), apisv1.CreateNotificationWebhookRequest{
+			Name:   "ci",
+			URL:    server.URL,
+			Secret: "secret",
+			Events: []string{model.EventApplicationDeployed},
+		})
+		Expect(err).Should(BeNil())
+		Expect(webhook.HasSecret).Should(BeTrue())
+		_, err = notificationUsecase.CreateWebhook(context.TODO(), apisv1.CreateNotificationWebhookRequest{Name: "ci", URL: server.URL})
+		Expect(err).Should(Equal(bcode.ErrNotificationWebhookExist))
+
+		By("the secret is kept if it is not set")
+		webhook, err = notificationUsecase.UpdateWebhook(context.TODO(), "ci", apisv1.UpdateNotificationWebhookRequest{
+			URL:    server.URL,
+			Events: []string{model.EventApplicationDeployed, model.EventUserCreated},
+		})
+		Expect(err).Should(BeNil())
+		Expect(webhook.HasSecret).Should(BeTrue())
+		Expect(len(webhook.Events)).Should(Equal(2))
+
+		list, err := notificationUsecase.ListWebhooks(context.TODO())
+		Expect(err).Should(BeNil())
+		Expect(len(list.Webhooks)).Should(Equal(1))
+		Expect(list.Events).Should(Equal(model.NotificationEvents))
+
+		Expect(notificationUsecase.DeleteWebhook(context.TODO(), "ci")).Should(Succeed())
+		Expect(notificationUsecase.DeleteWebhook(context.TODO(), "ci")).Should(Equal(bcode.ErrNotificationWebhookNotExist))
+	})
+
+	It("Test the event is dropped when the queue is full", func() {
+		n := &notificationUsecaseImpl{ds: ds, queue: make(chan apisv1.NotificationPayload, 1)}
+		n.Emit(context.TODO(), model.EventUserCreated, map[string]string{"user": "first"})
+		// the emit never blocks the caller
+		n.Emit(context.TODO(), model.EventUserCreated, map[string]string{"user": "second"})
+		Expect(len(n.queue)).Should(Equal(1))
+		payload := <-n.queue
+		Expect(payload.Data).Should(Equal(map[string]string{"user": "first"}))
+	})
+
+	It("Test deliver the event with the signature and retries", func() {
+		_, err := notificationUsecase.CreateWebhook(context.TODO(), apisv1.CreateNotificationWebhookRequest{
+			Name:   "deploy",
+			URL:    server.URL,
+			Secret: "secret",
+			Events: []string{model.EventApplicationDeployed},
+		})
+		Expect(err).Should(BeNil())
+
+		By("the unsubscribed event is not delivered")
+		notificationUsecase.Emit(context.TODO(), model.EventUserCreated, map[string]string{"user": "test"})
+
+		atomic.StoreInt32(&failures, 2)
+		notificationUsecase.Emit(context.TODO(), model.EventApplicationDeployed, map[string]string{"application": "app"})
+		Eventually(func() int {
+			deliveries, err := notificationUsecase.ListDeliveries(context.TODO(), "deploy", 0, 0)
+			Expect(err).Should(BeNil())
+			return len(deliveries.Deliveries)
+		}, time.Second*10, time.Millisecond*100).Should(Equal(1))
+
+		deliveries, err := notificationUsecase.ListDeliveries(context.TODO(), "deploy", 0, 0)
+		Expect(err).Should(BeNil())
+		delivery := deliveries.Deliveries[0]
+		Expect(delivery.Status).Should(Equal(model.DeliveryStatusSuccess))
+		Expect(delivery.Event).Should(Equal(model.EventApplicationDeployed))
+		Expect(delivery.Attempts).Should(Equal(3))
+		Expect(atomic.LoadInt32(&requests)).Should(Equal(int32(3)))
+		Expect(signatureValid.Load()).Should(Equal(true))
+	})
+
+	It("Test the retries are stopped when the context is canceled", func() {
+		ctx, cancelDelivery := context.WithCancel(context.Background())
+		defer cancelDelivery()
+		canceled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cancelDelivery()
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer canceled.Close()
+		start := time.Now()
+		delivery := notificationUsecase.deliver(ctx, &model.NotificationWebhook{Name: "canceled", URL: canceled.URL},
+			apisv1.NotificationPayload{Event: model.EventUserCreated}, wait.Backoff{Steps: 3, Duration: time.Hour})
+		Expect(time.Since(start)).Should(BeNumerically("<", time.Minute))
+		Expect(delivery.Attempts).Should(Equal(1))
+		Expect(delivery.Status).Should(Equal(model.DeliveryStatusFailure))
+	})
+
+	It("Test send a test event to the webhook", func() {
+		_, err := notificationUsecase.TestWebhook(context.TODO(), "not-exist")
+		Expect(err).Should(Equal(bcode.ErrNotificationWebhookNotExist))
+
+		_, err = notificationUsecase.CreateWebhook(context.TODO(), apisv1.CreateNotificationWebhookRequest{
+			Name:     "test",
+			URL:      server.URL,
+			Disabled: true,
+		})
+		Expect(err).Should(BeNil())
+
+		By("the failed test event is not retried")
+		atomic.StoreInt32(&failures, 1)
+		delivery, err := notificationUsecase.TestWebhook(context.TODO(), "test")
+		Expect(err).Should(BeNil())
+		Expect(delivery.Status).Should(Equal(model.DeliveryStatusFailure))
+		Expect(delivery.StatusCode).Should(Equal(http.StatusInternalServerError))
+		Expect(delivery.Attempts).Should(Equal(1))
+
+		delivery, err = notificationUsecase.TestWebhook(context.TODO(), "test")
+		Expect(err).Should(BeNil())
+		Expect(delivery.Status).Should(Equal(model.DeliveryStatusSuccess))
+
+		deliveries, err := notificationUsecase.ListDeliveries(context.TODO(), "test", 1, 10)
+		Expect(err).Should(BeNil())
+		Expect(deliveries.Total).Should(Equal(int64(2)))
+	})
+})
@@ -80,7 +80,7 @@ var _ = Describe("Test project usecase functions", func() {
 			_ = envImpl.DeleteEnv(context.TODO(), e.Name)
 		}
 		targetImpl = &targetUsecaseImpl{k8sClient: k8sClient, ds: ds}
-		targets, err := targetImpl.ListTargets(context.TODO(), 0, 0, "")
+		targets, err := targetImpl.ListTargets(context.TODO(), 0, 0, apisv1.ListTargetOptions{})
 		Expect(err).Should(BeNil())
 		// reset all projects
 		for _, t := range targets.Targets {
@@ -207,6 +207,9 @@ var ResourceMaps = map[string]resourceMetadata{
 	"role":          {},
 	"permission":    {},
 	"systemSetting": {},
+	"notification": {
+		pathName: "webhookName",
+	},
 	"definition": {
 		pathName: "definitionName",
 	},
@@ -507,17 +510,11 @@ func (p *rbacUsecaseImpl) CheckPerm(resource string, actions ...string) func(req
 			return
 		}
 
-		// multiple method for get the project name.
-		getProjectName := func() string {
+		// get the project name from the resource path
+		getPathProjectName := func() string {
 			if value := req.PathParameter("projectName"); value != "" {
 				return value
 			}
-			if value := req.QueryParameter("project"); value != "" {
-				return value
-			}
-			if value := req.QueryParameter("projectName"); value != "" {
-				return value
-			}
 			if appName := req.PathParameter(ResourceMaps["project"].subResources["application"].pathName); appName != "" {
 				app := &model.Application{Name: appName}
 				if err := p.ds.Get(req.Request.Context(), app); err == nil {
@@ -532,6 +529,30 @@ func (p *rbacUsecaseImpl) CheckPerm(resource string, actions ...string) func(req
 			}
 			return ""
 		}
+		// multiple method for get the project name.
+		getProjectName := func() string {
+			if value := req.PathParameter("projectName"); value != "" {
+				return value
+			}
+			if value := req.QueryParameter("project"); value != "" {
+				return value
+			}
+			if value := req.QueryParameter("projectName"); value != "" {
+				return value
+			}
+			return getPathProjectName()
+		}
+
+		// the project scoped token can only access the resources of the project tree,
+		// the project is got from the resource path, so the token can not escape the scope by the query parameters.
+		if IsProjectScopedAPIToken(req.Request.Context()) {
+			if !strings.HasPrefix(path, "project:") || !CheckAPITokenProject(req.Request.Context(), getPathProjectName()) {
+				bcode.ReturnError(req, res, bcode.ErrAPITokenScopeForbidden)
+				return
+			}
+			getProjectName = getPathProjectName
+			req.SetAttribute(APITokenScopeCheckedAttribute, true)
+		}
 
 		ra := &RequestResourceAction{}
 		ra.SetResourceWithName(path, func(name string) string {
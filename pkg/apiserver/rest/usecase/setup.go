@@ -0,0 +1,284 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	velatypes "github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/apiserver/clients"
+	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/log"
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+	v1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
+)
+
+// SetupUsecase is the usecase of the setup wizard, which initializes a new installation only once
+type SetupUsecase interface {
+	GetSetupStatus(ctx context.Context) (*v1.SystemSetupStatusResponse, error)
+	Setup(ctx context.Context, req v1.SystemSetupRequest) (*v1.SystemSetupStatusResponse, error)
+}
+
+type setupUsecaseImpl struct {
+	ds                  datastore.DataStore
+	kubeClient          client.Client
+	systemInfoUsecase   SystemInfoUsecase
+	notificationUsecase NotificationUsecase
+	cache               *Cache
+}
+
+// NewSetupUsecase new setup usecase
+func NewSetupUsecase(ds datastore.DataStore, systemInfoUsecase SystemInfoUsecase, notificationUsecase NotificationUsecase, cache *Cache) SetupUsecase {
+	kubecli, err := clients.GetKubeClient()
+	if err != nil {
+		log.Logger.Fatalf("failed to get kube client: %s", err.Error())
+	}
+	return &setupUsecaseImpl{ds: ds, kubeClient: kubecli, systemInfoUsecase: systemInfoUsecase, notificationUsecase: notificationUsecase, cache: cache}
+}
+
+// GetSetupStatus returns whether the system has been initialized
+func (s *setupUsecaseImpl) GetSetupStatus(ctx context.Context) (*v1.SystemSetupStatusResponse, error) {
+	info, err := s.systemInfoUsecase.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	initialized, err := s.isInitialized(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.SystemSetupStatusResponse{Initialized: initialized, LoginType: info.LoginType}, nil
+}
+
+// Setup sets the address of VelaUX, the admin account, the login type and the collection choice at once.
+// All settings are validated before anything is written, and the admin account and the dex config are reverted if the system info can not be saved.
+func (s *setupUsecaseImpl) Setup(ctx context.Context, req v1.SystemSetupRequest) (resp *v1.SystemSetupStatusResponse, err error) {
+	info, err := s.systemInfoUsecase.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	initialized, err := s.isInitialized(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	if initialized {
+		return nil, bcode.ErrSystemInitialized
+	}
+
+	previousLoginType := info.LoginType
+	velaAddress := strings.TrimSuffix(req.VelaAddress, "/")
+	loginType := req.LoginType
+	if loginType == "" {
+		loginType = model.LoginTypeLocal
+	}
+	oidcConfig := info.OIDCConfig
+	var connectors []map[string]interface{}
+	switch loginType {
+	case model.LoginTypeOIDC:
+		if req.OIDCConfig != nil {
+			oidcConfig = mergeOIDCConfig(info.OIDCConfig, req.OIDCConfig)
+		}
+		if err := validateOIDCConfig(ctx, oidcConfig); err != nil {
+			return nil, err
+		}
+	case model.LoginTypeDex:
+		connectors, err = utils.GetDexConnectors(ctx, s.kubeClient)
+		if err != nil {
+			return nil, err
+		}
+		if len(connectors) < 1 {
+			return nil, bcode.ErrNoDexConnector
+		}
+	}
+	hash, err := GeneratePasswordHash(req.AdminPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	// the marker could only be added once, it makes sure the concurrent setup requests could not both pass the initialized check
+	marker := &model.SystemSetup{Name: model.SystemSetupName}
+	if err := s.ds.Add(ctx, marker); err != nil {
+		if errors.Is(err, datastore.ErrRecordExist) {
+			return nil, bcode.ErrSystemInitialized
+		}
+		return nil, err
+	}
+	defer func() {
+		// release the marker if the setup fails, so the setup could be retried
+		if err != nil {
+			if err := s.ds.Delete(ctx, marker); err != nil {
+				log.Logger.Errorf("failed to delete the setup marker after the setup failure: %s", err.Error())
+			}
+		}
+	}()
+
+	previousDexSecret, err := s.getDexSecret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	admin, previous, err := s.setupAdmin(ctx, req.AdminEmail, hash)
+	if err != nil {
+		return nil, err
+	}
+	revertAdmin := func() {
+		var err error
+		if previous == nil {
+			err = s.ds.Delete(ctx, admin)
+		} else {
+			err = s.ds.Put(ctx, previous)
+		}
+		if err != nil {
+			log.Logger.Errorf("failed to revert the admin user after the setup failure: %s", err.Error())
+		}
+	}
+
+	dexConfig := &model.UpdateDexConfig{
+		VelaAddress: velaAddress,
+		Connectors:  connectors,
+	}
+	// the existing static passwords are kept unless the dex login is chosen
+	if loginType == model.LoginTypeDex {
+		dexConfig.StaticPasswords = []model.StaticPassword{
+			{
+				Email:    admin.Email,
+				Hash:     admin.Password,
+				Username: admin.Name,
+			},
+		}
+	}
+	if err := generateDexConfig(ctx, s.kubeClient, dexConfig); err != nil {
+		revertAdmin()
+		s.restoreDexSecret(ctx, previousDexSecret)
+		return nil, err
+	}
+
+	info.VelaAddress = velaAddress
+	info.LoginType = loginType
+	info.OIDCConfig = oidcConfig
+	info.EnableCollection = req.EnableCollection
+	if req.CollectionConfig != nil {
+		info.CollectionConfig = req.CollectionConfig
+	}
+	info.Initialized = true
+	info.UpdateTime = time.Now()
+	if err := s.ds.Put(ctx, info); err != nil {
+		revertAdmin()
+		s.restoreDexSecret(ctx, previousDexSecret)
+		return nil, err
+	}
+	s.cache.Invalidate(cacheGroupSystemInfo)
+	emitLoginTypeChanged(ctx, s.notificationUsecase, previousLoginType, loginType)
+	log.Logger.Infof("the system is set up with the login type %s", loginType)
+	return &v1.SystemSetupStatusResponse{Initialized: true, LoginType: loginType}, nil
+}
+
+// getDexSecret returns a copy of the secret of the dex config, it is nil if the secret does not exist
+func (s *setupUsecaseImpl) getDexSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := s.kubeClient.Get(ctx, types.NamespacedName{Name: dexConfigName, Namespace: velatypes.DefaultKubeVelaNS}, secret); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret.DeepCopy(), nil
+}
+
+// restoreDexSecret restores the dex config to the one before the setup, the secret is deleted if it did not exist
+func (s *setupUsecaseImpl) restoreDexSecret(ctx context.Context, previous *corev1.Secret) {
+	current, err := s.getDexSecret(ctx)
+	if err == nil {
+		switch {
+		case current == nil:
+			if previous != nil {
+				previous.ResourceVersion = ""
+				err = s.kubeClient.Create(ctx, previous)
+			}
+		case previous == nil:
+			err = s.kubeClient.Delete(ctx, current)
+		case !reflect.DeepEqual(current.Data, previous.Data):
+			current.Data = previous.Data
+			err = s.kubeClient.Update(ctx, current)
+		default:
+			return
+		}
+	}
+	if err != nil {
+		log.Logger.Errorf("failed to restore the dex config after the setup failure: %s", err.Error())
+		return
+	}
+	if err := restartDex(ctx, s.kubeClient); err != nil && !errors.Is(err, bcode.ErrDexNotFound) {
+		log.Logger.Errorf("failed to restart the dex: %s", err.Error())
+	}
+}
+
+// setupAdmin creates or updates the admin user, it returns the admin user before updating, which is nil if the admin user is created
+func (s *setupUsecaseImpl) setupAdmin(ctx context.Context, email, passwordHash string) (*model.User, *model.User, error) {
+	admin := &model.User{Name: model.DefaultAdminUserName}
+	if err := s.ds.Get(ctx, admin); err != nil {
+		if !errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, nil, err
+		}
+		admin = &model.User{
+			Name:      model.DefaultAdminUserName,
+			Alias:     model.DefaultAdminUserAlias,
+			Email:     email,
+			Password:  passwordHash,
+			UserRoles: []string{"admin"},
+		}
+		if err := s.ds.Add(ctx, admin); err != nil {
+			return nil, nil, err
+		}
+		return admin, nil, nil
+	}
+	previous := *admin
+	admin.Email = email
+	admin.Password = passwordHash
+	if err := s.ds.Put(ctx, admin); err != nil {
+		return nil, nil, err
+	}
+	return admin, &previous, nil
+}
+
+// isInitialized checks whether the system has been set up. The installations before the setup wizard
+// are regarded as initialized once the login type or the default password of the admin user is changed.
+func (s *setupUsecaseImpl) isInitialized(ctx context.Context, info *model.SystemInfo) (bool, error) {
+	if info.Initialized {
+		return true, nil
+	}
+	if info.LoginType != "" && info.LoginType != model.LoginTypeLocal {
+		return true, nil
+	}
+	admin := &model.User{Name: model.DefaultAdminUserName}
+	if err := s.ds.Get(ctx, admin); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return compareHashWithPassword(admin.Password, initAdminPassword) != nil, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
+)
+
+var _ = Describe("Test setup usecase functions", func() {
+	var (
+		setupUsecase *setupUsecaseImpl
+		ds           datastore.DataStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		ds, err = NewDatastore(datastore.Config{Type: "kubeapi", Database: "setup-test-" + strconv.FormatInt(time.Now().UnixNano(), 10)})
+		Expect(ds).ToNot(BeNil())
+		Expect(err).Should(BeNil())
+		sysUsecase := &systemInfoUsecaseImpl{ds: ds, kubeClient: k8sClient}
+		setupUsecase = &setupUsecaseImpl{ds: ds, kubeClient: k8sClient, systemInfoUsecase: sysUsecase}
+		userUsecase := &userUsecaseImpl{ds: ds, k8sClient: k8sClient, sysUsecase: sysUsecase}
+		Expect(userUsecase.Init(context.TODO())).Should(Succeed())
+	})
+
+	It("Test set up the system only once", func() {
+		status, err := setupUsecase.GetSetupStatus(context.TODO())
+		Expect(err).Should(BeNil())
+		Expect(status.Initialized).Should(BeFalse())
+
+		status, err = setupUsecase.Setup(context.TODO(), apisv1.SystemSetupRequest{
+			VelaAddress:      "http://velaux.example.com/",
+			AdminEmail:       "admin@example.com",
+			AdminPassword:    "Setup12345",
+			EnableCollection: false,
+		})
+		Expect(err).Should(BeNil())
+		Expect(status.Initialized).Should(BeTrue())
+		Expect(status.LoginType).Should(Equal(model.LoginTypeLocal))
+
+		info, err := setupUsecase.systemInfoUsecase.Get(context.TODO())
+		Expect(err).Should(BeNil())
+		Expect(info.VelaAddress).Should(Equal("http://velaux.example.com"))
+		Expect(info.EnableCollection).Should(BeFalse())
+		admin := &model.User{Name: model.DefaultAdminUserName}
+		Expect(ds.Get(context.TODO(), admin)).Should(Succeed())
+		Expect(admin.Email).Should(Equal("admin@example.com"))
+		Expect(compareHashWithPassword(admin.Password, "Setup12345")).Should(BeNil())
+		dexConfig, err := getDexConfig(context.TODO(), k8sClient)
+		Expect(err).Should(BeNil())
+		Expect(dexConfig.Issuer).Should(Equal("http://velaux.example.com/dex"))
+
+		By("the setup is refused once the system has been initialized")
+		_, err = setupUsecase.Setup(context.TODO(), apisv1.SystemSetupRequest{
+			VelaAddress:   "http://attacker.example.com",
+			AdminEmail:    "attacker@example.com",
+			AdminPassword: "Attack12345",
+		})
+		Expect(err).Should(Equal(bcode.ErrSystemInitialized))
+		Expect(ds.Get(context.TODO(), admin)).Should(Succeed())
+		Expect(admin.Email).Should(Equal("admin@example.com"))
+	})
+
+	It("Test the concurrent setup is refused by the setup marker", func() {
+		By("another replica is setting up the system")
+		Expect(ds.Add(context.TODO(), &model.SystemSetup{Name: model.SystemSetupName})).Should(Succeed())
+		_, err := setupUsecase.Setup(context.TODO(), apisv1.SystemSetupRequest{
+			VelaAddress:   "http://velaux.example.com",
+			AdminEmail:    "admin@example.com",
+			AdminPassword: "Setup12345",
+		})
+		Expect(err).Should(Equal(bcode.ErrSystemInitialized))
+		admin := &model.User{Name: model.DefaultAdminUserName}
+		Expect(ds.Get(context.TODO(), admin)).Should(Succeed())
+		Expect(admin.Email).ShouldNot(Equal("admin@example.com"))
+	})
+
+	It("Test the static passwords of dex are kept by the setup without the dex login", func() {
+		staticPasswords := []model.StaticPassword{{Email: "dex@example.com", Hash: "hash", Username: "dex"}}
+		Expect(generateDexConfig(context.TODO(), k8sClient, &model.UpdateDexConfig{StaticPasswords: staticPasswords})).Should(Succeed())
+		_, err := setupUsecase.Setup(context.TODO(), apisv1.SystemSetupRequest{
+			VelaAddress:   "http://velaux.example.com",
+			AdminEmail:    "admin@example.com",
+			AdminPassword: "Setup12345",
+		})
+		Expect(err).Should(BeNil())
+		dexConfig, err := getDexConfig(context.TODO(), k8sClient)
+		Expect(err).Should(BeNil())
+		Expect(dexConfig.StaticPasswords).Should(Equal(staticPasswords))
+	})
+
+	It("Test the installation before the setup wizard is initialized", func() {
+		admin := &model.User{Name: model.DefaultAdminUserName}
+		Expect(ds.Get(context.TODO(), admin)).Should(Succeed())
+		hash, err := GeneratePasswordHash("Changed12345")
+		Expect(err).Should(BeNil())
+		admin.Password = hash
+		Expect(ds.Put(context.TODO(), admin)).Should(Succeed())
+
+		status, err := setupUsecase.GetSetupStatus(context.TODO())
+		Expect(err).Should(BeNil())
+		Expect(status.Initialized).Should(BeTrue())
+	})
+})
@@ -20,6 +20,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/coreos/go-oidc"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -38,25 +39,62 @@ type SystemInfoUsecase interface {
 	Get(ctx context.Context) (*model.SystemInfo, error)
 	GetSystemInfo(ctx context.Context) (*v1.SystemInfoResponse, error)
 	UpdateSystemInfo(ctx context.Context, sysInfo v1.SystemInfoRequest) (*v1.SystemInfoResponse, error)
+	ExportSystemConfig(ctx context.Context, includeSecrets bool) (*v1.SystemConfigBundle, error)
+	ImportSystemConfig(ctx context.Context, bundle v1.SystemConfigBundle) (*v1.ImportSystemConfigResponse, error)
+	GetCollectionPreview(ctx context.Context) (*v1.CollectionPreviewResponse, error)
 	Init(ctx context.Context) error
 }
 
+// the system info is also updated by the statistic job and the other replicas, so the cached one is expired soon.
+// All writes of this replica invalidate the cache, including the login type and the setup state read by the authentication.
+const systemInfoCacheTTL = time.Minute
+
 type systemInfoUsecaseImpl struct {
-	ds         datastore.DataStore
-	kubeClient client.Client
+	ds                  datastore.DataStore
+	kubeClient          client.Client
+	notificationUsecase NotificationUsecase
+	cache               *Cache
 }
 
 // NewSystemInfoUsecase return a systemInfoCollectionUsecase
-func NewSystemInfoUsecase(ds datastore.DataStore) SystemInfoUsecase {
+func NewSystemInfoUsecase(ds datastore.DataStore, notificationUsecase NotificationUsecase, cache *Cache) SystemInfoUsecase {
 	kubecli, err := clients.GetKubeClient()
 	if err != nil {
 		log.Logger.Fatalf("failed to get kube client: %s", err.Error())
 	}
-	return &systemInfoUsecaseImpl{ds: ds, kubeClient: kubecli}
+	return &systemInfoUsecaseImpl{ds: ds, kubeClient: kubecli, notificationUsecase: notificationUsecase, cache: cache}
 }
 
+// Get returns the system info, the callers could modify the returned one without affecting the cache
 func (u systemInfoUsecaseImpl) Get(ctx context.Context) (*model.SystemInfo, error) {
-	// first get request will init systemInfoCollection{installId: {random}, enableCollection: true}
+	if data, ok := u.cache.Get(cacheGroupSystemInfo, ""); ok {
+		return copySystemInfo(data.(*model.SystemInfo)), nil
+	}
+	info, err := u.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u.cache.Put(cacheGroupSystemInfo, "", copySystemInfo(info), systemInfoCacheTTL)
+	return info, nil
+}
+
+// copySystemInfo copies the system info and its configs, so the cached one is not affected by the callers
+func copySystemInfo(info *model.SystemInfo) *model.SystemInfo {
+	copied := *info
+	if info.OIDCConfig != nil {
+		oidcConfig := *info.OIDCConfig
+		copied.OIDCConfig = &oidcConfig
+	}
+	if info.CollectionConfig != nil {
+		collectionConfig := *info.CollectionConfig
+		copied.CollectionConfig = &collectionConfig
+	}
+	return &copied
+}
+
+func (u systemInfoUsecaseImpl) get(ctx context.Context) (*model.SystemInfo, error) {
+	// first get request will init systemInfoCollection{installId: {random}, enableCollection: true},
+	// the system is not initialized until the setup is done.
 	info := &model.SystemInfo{}
 	entities, err := u.ds.List(ctx, info, &datastore.ListOptions{})
 	if err != nil {
@@ -87,22 +125,19 @@ func (u systemInfoUsecaseImpl) GetSystemInfo(ctx context.Context) (*v1.SystemInf
 	if err != nil {
 		return nil, err
 	}
-	return &v1.SystemInfoResponse{
-		SystemInfo: convertInfoToBase(info),
-		SystemVersion: v1.SystemVersion{
-			VelaVersion: version.VelaVersion,
-			GitVersion:  version.GitRevision,
-		},
-		StatisticInfo: v1.StatisticInfo{
-			AppCount:                   info.StatisticInfo.AppCount,
-			ClusterCount:               info.StatisticInfo.ClusterCount,
-			EnableAddonList:            info.StatisticInfo.EnabledAddon,
-			ComponentDefinitionTopList: info.StatisticInfo.TopKCompDef,
-			TraitDefinitionTopList:     info.StatisticInfo.TopKTraitDef,
-			WorkflowDefinitionTopList:  info.StatisticInfo.TopKWorkflowStepDef,
-			PolicyDefinitionTopList:    info.StatisticInfo.TopKPolicyDef,
-			UpdateTime:                 info.StatisticInfo.UpdateTime,
-		},
+	return convertSystemInfoResponse(info), nil
+}
+
+// GetCollectionPreview returns exactly the payload that would be reported or exported
+func (u systemInfoUsecaseImpl) GetCollectionPreview(ctx context.Context) (*v1.CollectionPreviewResponse, error) {
+	info, err := u.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.CollectionPreviewResponse{
+		EnableCollection: info.EnableCollection,
+		ExportOnly:       info.GetCollectionConfig().ExportOnly,
+		Payload:          buildCollectionPayload(info),
 	}, nil
 }
 
@@ -115,14 +150,28 @@ func (u systemInfoUsecaseImpl) UpdateSystemInfo(ctx context.Context, sysInfo v1.
 		InstallID:        info.InstallID,
 		EnableCollection: sysInfo.EnableCollection,
 		LoginType:        sysInfo.LoginType,
+		OIDCConfig:       info.OIDCConfig,
+		CollectionConfig: info.CollectionConfig,
+		VelaAddress:      info.VelaAddress,
+		Initialized:      info.Initialized,
 		BaseModel: model.BaseModel{
 			CreateTime: info.CreateTime,
 			UpdateTime: time.Now(),
 		},
 		StatisticInfo: info.StatisticInfo,
 	}
+	if sysInfo.VelaAddress != "" {
+		modifiedInfo.VelaAddress = sysInfo.VelaAddress
+	}
 
-	if sysInfo.LoginType == model.LoginTypeDex {
+	if sysInfo.OIDCConfig != nil {
+		modifiedInfo.OIDCConfig = mergeOIDCConfig(info.OIDCConfig, sysInfo.OIDCConfig)
+	}
+	if sysInfo.CollectionConfig != nil {
+		modifiedInfo.CollectionConfig = sysInfo.CollectionConfig
+	}
+
+	if sysInfo.LoginType == model.LoginTypeDex || sysInfo.LoginType == model.LoginTypeOIDC {
 		admin := &model.User{Name: model.DefaultAdminUserName}
 		if err := u.ds.Get(ctx, admin); err != nil {
 			return nil, err
@@ -130,6 +179,14 @@ func (u systemInfoUsecaseImpl) UpdateSystemInfo(ctx context.Context, sysInfo v1.
 		if admin.Email == "" {
 			return nil, bcode.ErrEmptyAdminEmail
 		}
+	}
+
+	switch sysInfo.LoginType {
+	case model.LoginTypeOIDC:
+		if err := validateOIDCConfig(ctx, modifiedInfo.OIDCConfig); err != nil {
+			return nil, err
+		}
+	case model.LoginTypeDex:
 		connectors, err := utils.GetDexConnectors(ctx, u.kubeClient)
 		if err != nil {
 			return nil, err
@@ -138,7 +195,7 @@ func (u systemInfoUsecaseImpl) UpdateSystemInfo(ctx context.Context, sysInfo v1.
 			return nil, bcode.ErrNoDexConnector
 		}
 		if err := generateDexConfig(ctx, u.kubeClient, &model.UpdateDexConfig{
-			VelaAddress: sysInfo.VelaAddress,
+			VelaAddress: modifiedInfo.VelaAddress,
 			Connectors:  connectors,
 		}); err != nil {
 			return nil, err
@@ -148,16 +205,11 @@ func (u systemInfoUsecaseImpl) UpdateSystemInfo(ctx context.Context, sysInfo v1.
 	if err != nil {
 		return nil, err
 	}
-	return &v1.SystemInfoResponse{
-		SystemInfo: v1.SystemInfo{
-			PlatformID:       modifiedInfo.InstallID,
-			EnableCollection: modifiedInfo.EnableCollection,
-			LoginType:        modifiedInfo.LoginType,
-			// always use the initial createTime as system's installTime
-			InstallTime: info.CreateTime,
-		},
-		SystemVersion: v1.SystemVersion{VelaVersion: version.VelaVersion, GitVersion: version.GitRevision},
-	}, nil
+	u.cache.Invalidate(cacheGroupSystemInfo)
+	emitLoginTypeChanged(ctx, u.notificationUsecase, info.LoginType, modifiedInfo.LoginType)
+	// always use the initial createTime as system's installTime
+	modifiedInfo.CreateTime = info.CreateTime
+	return convertSystemInfoResponse(&modifiedInfo), nil
 }
 
 func (u systemInfoUsecaseImpl) Init(ctx context.Context) error {
@@ -166,7 +218,8 @@ func (u systemInfoUsecaseImpl) Init(ctx context.Context) error {
 		return err
 	}
 	signedKey = info.InstallID
-	_, err = initDexConfig(ctx, u.kubeClient, "http://velaux.com")
+	// the default address is used if the address of VelaUX is not set yet, it is updated by the setup
+	_, err = initDexConfig(ctx, u.kubeClient, info.VelaAddress)
 	return err
 }
 
@@ -175,6 +228,127 @@ func convertInfoToBase(info *model.SystemInfo) v1.SystemInfo {
 		PlatformID:       info.InstallID,
 		EnableCollection: info.EnableCollection,
 		LoginType:        info.LoginType,
+		OIDCConfig:       convertOIDCConfigBase(info.OIDCConfig),
+		CollectionConfig: info.GetCollectionConfig(),
 		InstallTime:      info.CreateTime,
+		VelaAddress:      info.VelaAddress,
+	}
+}
+
+// buildCollectionPayload builds the payload that would be reported or exported, only the categories enabled by the collection config are included
+func buildCollectionPayload(info *model.SystemInfo) v1.CollectionPayload {
+	payload := v1.CollectionPayload{PlatformID: info.InstallID}
+	if !info.EnableCollection {
+		return payload
+	}
+	config := info.GetCollectionConfig()
+	if config.VersionInfo {
+		payload.SystemVersion = &v1.SystemVersion{
+			VelaVersion: version.VelaVersion,
+			GitVersion:  version.GitRevision,
+		}
+	}
+	if config.UsageStatistics || config.DefinitionTopList {
+		payload.StatisticInfo = convertStatisticInfo(info.StatisticInfo, config)
+	}
+	return payload
+}
+
+// convertSystemInfoResponse builds the system info displayed by VelaUX, all fields are returned whatever the collection config is.
+// The collection config only applies to the payload built by buildCollectionPayload.
+func convertSystemInfoResponse(info *model.SystemInfo) *v1.SystemInfoResponse {
+	return &v1.SystemInfoResponse{
+		SystemInfo: convertInfoToBase(info),
+		SystemVersion: v1.SystemVersion{
+			VelaVersion: version.VelaVersion,
+			GitVersion:  version.GitRevision,
+		},
+		StatisticInfo: *convertStatisticInfo(info.StatisticInfo, model.CollectionConfig{UsageStatistics: true, DefinitionTopList: true}),
+	}
+}
+
+// convertStatisticInfo only keeps the categories enabled by the collection config
+func convertStatisticInfo(info model.StatisticInfo, config model.CollectionConfig) *v1.StatisticInfo {
+	statistic := &v1.StatisticInfo{UpdateTime: info.UpdateTime}
+	if config.UsageStatistics {
+		statistic.AppCount = info.AppCount
+		statistic.ClusterCount = info.ClusterCount
+		statistic.EnableAddonList = info.EnabledAddon
+	}
+	if config.DefinitionTopList {
+		statistic.ComponentDefinitionTopList = info.TopKCompDef
+		statistic.TraitDefinitionTopList = info.TopKTraitDef
+		statistic.WorkflowDefinitionTopList = info.TopKWorkflowStepDef
+		statistic.PolicyDefinitionTopList = info.TopKPolicyDef
+	}
+	return statistic
+}
+
+func convertOIDCConfigBase(config *model.OIDCConfig) *v1.OIDCConfigBase {
+	if config == nil {
+		return nil
+	}
+	return &v1.OIDCConfigBase{
+		Issuer:               config.Issuer,
+		ClientID:             config.ClientID,
+		RedirectURL:          config.RedirectURL,
+		Scopes:               config.Scopes,
+		EmailClaim:           config.EmailClaim,
+		NameClaim:            config.NameClaim,
+		AllowUnverifiedEmail: config.AllowUnverifiedEmail,
+	}
+}
+
+// mergeOIDCConfig builds the new oidc config from the request,
+// the existing client secret is kept if the request does not set a new one.
+func mergeOIDCConfig(existing *model.OIDCConfig, req *v1.OIDCConfigRequest) *model.OIDCConfig {
+	config := &model.OIDCConfig{
+		// the issuer must be kept as it is, it is compared with the one in the discovery document
+		Issuer:               req.Issuer,
+		ClientID:             req.ClientID,
+		ClientSecret:         req.ClientSecret,
+		RedirectURL:          req.RedirectURL,
+		Scopes:               req.Scopes,
+		EmailClaim:           req.EmailClaim,
+		NameClaim:            req.NameClaim,
+		AllowUnverifiedEmail: req.AllowUnverifiedEmail,
+	}
+	if config.ClientSecret == "" && existing != nil {
+		config.ClientSecret = existing.ClientSecret
+	}
+	return config
+}
+
+// validateOIDCConfig checks the required fields and makes sure the discovery document of the issuer is reachable,
+// so that switching the login type can not lock out all users with an unusable config.
+func validateOIDCConfig(ctx context.Context, config *model.OIDCConfig) error {
+	if config == nil {
+		return bcode.ErrOIDCConfigNotFound
+	}
+	if config.Issuer == "" || config.ClientID == "" || config.ClientSecret == "" || config.RedirectURL == "" {
+		return bcode.ErrInvalidOIDCConfig
+	}
+	if _, err := oidc.NewProvider(ctx, config.Issuer); err != nil {
+		log.Logger.Errorf("failed to discover the oidc provider %s: %s", config.Issuer, err.Error())
+		return bcode.ErrInvalidOIDCConfig
+	}
+	return nil
+}
+
+// emitLoginTypeChanged emits the event if the login type is changed, the empty login type means local
+func emitLoginTypeChanged(ctx context.Context, n NotificationUsecase, from, to string) {
+	if from == "" {
+		from = model.LoginTypeLocal
+	}
+	if to == "" {
+		to = model.LoginTypeLocal
+	}
+	if from == to {
+		return
+	}
+	var operator string
+	if userName, ok := ctx.Value(&v1.CtxKeyUser).(string); ok {
+		operator = userName
 	}
+	emitNotification(ctx, n, model.EventLoginTypeChanged, map[string]string{"from": from, "to": to, "operator": operator})
 }
@@ -0,0 +1,482 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/log"
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+	v1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
+	"github.com/oam-dev/kubevela/pkg/utils/config"
+)
+
+// SystemConfigBundleVersion is the version of the system config bundle generated by this apiserver
+const SystemConfigBundleVersion = "v1"
+
+// ExportSystemConfig exports all state managed by the apiserver,
+// the secrets are excluded unless includeSecrets is true.
+func (u systemInfoUsecaseImpl) ExportSystemConfig(ctx context.Context, includeSecrets bool) (*v1.SystemConfigBundle, error) {
+	info, err := u.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &v1.SystemConfigBundle{
+		Version:        SystemConfigBundleVersion,
+		ExportTime:     time.Now(),
+		IncludeSecrets: includeSecrets,
+	}
+
+	sysInfo := *info
+	// the statistic info is recalculated by the new installation
+	sysInfo.StatisticInfo = model.StatisticInfo{}
+	if sysInfo.OIDCConfig != nil {
+		oidcConfig := *sysInfo.OIDCConfig
+		if !includeSecrets {
+			oidcConfig.ClientSecret = ""
+		}
+		sysInfo.OIDCConfig = &oidcConfig
+	}
+	bundle.SystemInfo = &sysInfo
+
+	users, err := u.ds.List(ctx, &model.User{}, &datastore.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range users {
+		user := entity.(*model.User)
+		if !includeSecrets {
+			user.Password = ""
+		}
+		bundle.Users = append(bundle.Users, user)
+	}
+	projectUsers, err := u.ds.List(ctx, &model.ProjectUser{}, &datastore.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range projectUsers {
+		bundle.ProjectUsers = append(bundle.ProjectUsers, entity.(*model.ProjectUser))
+	}
+	roles, err := u.ds.List(ctx, &model.Role{}, &datastore.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range roles {
+		bundle.Roles = append(bundle.Roles, entity.(*model.Role))
+	}
+	permissions, err := u.ds.List(ctx, &model.Permission{}, &datastore.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range permissions {
+		bundle.Permissions = append(bundle.Permissions, entity.(*model.Permission))
+	}
+	projects, err := u.ds.List(ctx, &model.Project{}, &datastore.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range projects {
+		bundle.Projects = append(bundle.Projects, entity.(*model.Project))
+	}
+	targets, err := u.ds.List(ctx, &model.Target{}, &datastore.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range targets {
+		bundle.Targets = append(bundle.Targets, entity.(*model.Target))
+	}
+	envs, err := u.ds.List(ctx, &model.Env{}, &datastore.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range envs {
+		bundle.Envs = append(bundle.Envs, entity.(*model.Env))
+	}
+
+	bundle.Integrations, err = u.exportIntegrations(ctx, includeSecrets)
+	if err != nil {
+		return nil, err
+	}
+
+	dexConfig, err := getDexConfig(ctx, u.kubeClient)
+	if err != nil && !errors.Is(err, bcode.ErrDexConfigNotFound) && !errors.Is(err, bcode.ErrInvalidDexConfig) {
+		return nil, err
+	}
+	if dexConfig != nil {
+		if !includeSecrets {
+			for i := range dexConfig.StaticClients {
+				dexConfig.StaticClients[i].Secret = ""
+			}
+			for i := range dexConfig.Connectors {
+				delete(dexConfig.Connectors[i], "config")
+			}
+			dexConfig.StaticPasswords = nil
+		}
+		bundle.DexConfig = dexConfig
+	}
+	return bundle, nil
+}
+
+func (u systemInfoUsecaseImpl) exportIntegrations(ctx context.Context, includeSecrets bool) ([]*v1.IntegrationBundle, error) {
+	var apps = &v1beta1.ApplicationList{}
+	if err := u.kubeClient.List(ctx, apps, client.InNamespace(types.DefaultKubeVelaNS),
+		client.MatchingLabels{
+			model.LabelSourceOfTruth: model.FromInner,
+			types.LabelConfigCatalog: types.VelaCoreConfig,
+		}); err != nil {
+		return nil, err
+	}
+	var integrations []*v1.IntegrationBundle
+	for _, app := range apps.Items {
+		if len(app.Spec.Components) < 1 {
+			continue
+		}
+		integration := &v1.IntegrationBundle{
+			Name:          app.Name,
+			Alias:         app.Annotations[types.AnnotationConfigAlias],
+			Description:   app.Annotations[types.AnnotationConfigDescription],
+			Project:       app.Labels[types.LabelConfigProject],
+			ComponentType: app.Labels[types.LabelConfigType],
+		}
+		if includeSecrets && app.Spec.Components[0].Properties != nil {
+			integration.Properties = string(app.Spec.Components[0].Properties.Raw)
+		}
+		integrations = append(integrations, integration)
+	}
+	return integrations, nil
+}
+
+// ImportSystemConfig restores the system config bundle, the existing records will be overwritten.
+// The records without secrets keep the secrets of the existing records.
+// The whole bundle is validated before the first write, and the previous state is restored if any write fails.
+func (u systemInfoUsecaseImpl) ImportSystemConfig(ctx context.Context, bundle v1.SystemConfigBundle) (*v1.ImportSystemConfigResponse, error) {
+	if bundle.Version != SystemConfigBundleVersion {
+		return nil, bcode.ErrUnsupportedBundleVersion
+	}
+	var info, previousInfo *model.SystemInfo
+	if bundle.SystemInfo != nil {
+		var err error
+		if info, previousInfo, err = u.mergeImportedSystemInfo(ctx, bundle.SystemInfo); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateBundle(bundle); err != nil {
+		return nil, err
+	}
+
+	rollback := &importRollback{ds: u.ds, kubeClient: u.kubeClient}
+	resp, err := u.importBundle(ctx, bundle, info, previousInfo, rollback)
+	if err != nil {
+		log.Logger.Errorf("failed to import the system config, restore the previous state: %s", err.Error())
+		rollback.restore(ctx)
+		u.cache.Invalidate(cacheGroupSystemInfo)
+		return nil, err
+	}
+	if info != nil {
+		u.cache.Invalidate(cacheGroupSystemInfo)
+		emitLoginTypeChanged(ctx, u.notificationUsecase, previousInfo.LoginType, info.LoginType)
+	}
+	return resp, nil
+}
+
+func (u systemInfoUsecaseImpl) importBundle(ctx context.Context, bundle v1.SystemConfigBundle, info, previousInfo *model.SystemInfo, rollback *importRollback) (*v1.ImportSystemConfigResponse, error) {
+	resp := &v1.ImportSystemConfigResponse{Imported: map[string]int{}}
+
+	if info != nil {
+		if err := u.ds.Put(ctx, info); err != nil {
+			return nil, err
+		}
+		rollback.record(info, previousInfo)
+		resp.Imported["systemInfo"] = 1
+	}
+
+	for _, user := range bundle.Users {
+		if user.Password == "" {
+			existing := &model.User{Name: user.Name}
+			if err := u.ds.Get(ctx, existing); err == nil {
+				user.Password = existing.Password
+			}
+		}
+		if err := rollback.upsert(ctx, user); err != nil {
+			return nil, err
+		}
+		resp.Imported["users"]++
+	}
+	for _, entity := range bundle.ProjectUsers {
+		if err := rollback.upsert(ctx, entity); err != nil {
+			return nil, err
+		}
+		resp.Imported["projectUsers"]++
+	}
+	for _, entity := range bundle.Roles {
+		if err := rollback.upsert(ctx, entity); err != nil {
+			return nil, err
+		}
+		resp.Imported["roles"]++
+	}
+	for _, entity := range bundle.Permissions {
+		if err := rollback.upsert(ctx, entity); err != nil {
+			return nil, err
+		}
+		resp.Imported["permissions"]++
+	}
+	for _, entity := range bundle.Projects {
+		if err := rollback.upsert(ctx, entity); err != nil {
+			return nil, err
+		}
+		resp.Imported["projects"]++
+	}
+	for _, entity := range bundle.Targets {
+		if err := rollback.upsert(ctx, entity); err != nil {
+			return nil, err
+		}
+		resp.Imported["targets"]++
+	}
+	for _, entity := range bundle.Envs {
+		if err := rollback.upsert(ctx, entity); err != nil {
+			return nil, err
+		}
+		resp.Imported["envs"]++
+	}
+
+	for _, integration := range bundle.Integrations {
+		if integration.Properties == "" {
+			resp.Skipped = append(resp.Skipped, fmt.Sprintf("integration %s: the properties are not included", integration.Name))
+			continue
+		}
+		err := config.CreateApplication(ctx, u.kubeClient, integration.Name, integration.ComponentType, integration.Properties, config.UIParam{
+			Alias:       integration.Alias,
+			Description: integration.Description,
+			Project:     integration.Project,
+		})
+		if err != nil {
+			if kerrors.IsAlreadyExists(err) {
+				resp.Skipped = append(resp.Skipped, fmt.Sprintf("integration %s: already exists", integration.Name))
+				continue
+			}
+			return nil, err
+		}
+		rollback.integrations = append(rollback.integrations, integration.Name)
+		resp.Imported["integrations"]++
+	}
+
+	// the dex config is restored at last, so it never needs to be rolled back
+	if bundle.DexConfig != nil {
+		// the dex config without secrets can not work, keep the existing one
+		if !bundle.IncludeSecrets {
+			resp.Skipped = append(resp.Skipped, "dexConfig: the secrets are not included")
+		} else {
+			if err := u.restoreDexConfig(ctx, bundle.DexConfig); err != nil {
+				return nil, err
+			}
+			resp.Imported["dexConfig"] = 1
+		}
+	}
+	return resp, nil
+}
+
+// mergeImportedSystemInfo builds the system info to be restored without writing it, the install ID of the current installation is kept.
+// It returns the merged system info and a copy of the current one.
+func (u systemInfoUsecaseImpl) mergeImportedSystemInfo(ctx context.Context, sysInfo *model.SystemInfo) (*model.SystemInfo, *model.SystemInfo, error) {
+	info, err := u.Get(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	previous := *info
+	info.EnableCollection = sysInfo.EnableCollection
+	info.CollectionConfig = sysInfo.CollectionConfig
+	info.LoginType = sysInfo.LoginType
+	if sysInfo.VelaAddress != "" {
+		info.VelaAddress = sysInfo.VelaAddress
+	}
+	if sysInfo.OIDCConfig != nil {
+		oidcConfig := *sysInfo.OIDCConfig
+		if oidcConfig.ClientSecret == "" && info.OIDCConfig != nil {
+			oidcConfig.ClientSecret = info.OIDCConfig.ClientSecret
+		}
+		info.OIDCConfig = &oidcConfig
+	}
+	if info.LoginType == model.LoginTypeOIDC {
+		if err := validateOIDCConfig(ctx, info.OIDCConfig); err != nil {
+			return nil, nil, err
+		}
+	}
+	info.UpdateTime = time.Now()
+	return info, &previous, nil
+}
+
+// validateBundle makes sure every record of the bundle could be written
+func validateBundle(bundle v1.SystemConfigBundle) error {
+	var entities []datastore.Entity
+	for _, entity := range bundle.Users {
+		entities = append(entities, entity)
+	}
+	for _, entity := range bundle.ProjectUsers {
+		entities = append(entities, entity)
+	}
+	for _, entity := range bundle.Roles {
+		entities = append(entities, entity)
+	}
+	for _, entity := range bundle.Permissions {
+		entities = append(entities, entity)
+	}
+	for _, entity := range bundle.Projects {
+		entities = append(entities, entity)
+	}
+	for _, entity := range bundle.Targets {
+		entities = append(entities, entity)
+	}
+	for _, entity := range bundle.Envs {
+		entities = append(entities, entity)
+	}
+	for _, entity := range entities {
+		if entity == nil || reflect.ValueOf(entity).IsNil() || entity.PrimaryKey() == "" {
+			return bcode.ErrInvalidBundle.SetMessage("the bundle contains a record without the primary key")
+		}
+	}
+	for _, integration := range bundle.Integrations {
+		if integration == nil || integration.Name == "" || integration.ComponentType == "" {
+			return bcode.ErrInvalidBundle.SetMessage("the bundle contains an integration without the name or type")
+		}
+	}
+	return nil
+}
+
+// importRollback records the state before the import, and restores it if the import fails
+type importRollback struct {
+	ds         datastore.DataStore
+	kubeClient client.Client
+	records    []importRecord
+	// integrations is the names of the created integrations
+	integrations []string
+}
+
+type importRecord struct {
+	entity datastore.Entity
+	// previous is nil if the entity is created by the import
+	previous datastore.Entity
+}
+
+func (r *importRollback) record(entity, previous datastore.Entity) {
+	r.records = append(r.records, importRecord{entity: entity, previous: previous})
+}
+
+// upsert adds or updates the entity, and records the previous state of it
+func (r *importRollback) upsert(ctx context.Context, entity datastore.Entity) error {
+	previous := newEntityWithKey(entity)
+	if err := r.ds.Get(ctx, previous); err != nil {
+		if !errors.Is(err, datastore.ErrRecordNotExist) {
+			return err
+		}
+		if err := r.ds.Add(ctx, entity); err != nil {
+			return err
+		}
+		r.record(entity, nil)
+		return nil
+	}
+	if err := r.ds.Put(ctx, entity); err != nil {
+		return err
+	}
+	r.record(entity, previous)
+	return nil
+}
+
+// restore reverts the writes in the reverse order, the failures are logged and skipped to restore as much as possible
+func (r *importRollback) restore(ctx context.Context) {
+	for _, name := range r.integrations {
+		app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: types.DefaultKubeVelaNS}}
+		if err := r.kubeClient.Delete(ctx, app); err != nil && !kerrors.IsNotFound(err) {
+			log.Logger.Errorf("failed to delete the imported integration %s: %s", name, err.Error())
+		}
+	}
+	for i := len(r.records) - 1; i >= 0; i-- {
+		record := r.records[i]
+		var err error
+		if record.previous == nil {
+			err = r.ds.Delete(ctx, record.entity)
+		} else {
+			err = r.ds.Put(ctx, record.previous)
+		}
+		if err != nil {
+			log.Logger.Errorf("failed to restore the %s record %s: %s", record.entity.TableName(), record.entity.PrimaryKey(), err.Error())
+		}
+	}
+}
+
+// newEntityWithKey returns an empty entity with the same primary key, it is used to read the stored one
+func newEntityWithKey(entity datastore.Entity) datastore.Entity {
+	switch e := entity.(type) {
+	case *model.User:
+		return &model.User{Name: e.Name}
+	case *model.ProjectUser:
+		return &model.ProjectUser{ProjectName: e.ProjectName, Username: e.Username}
+	case *model.Role:
+		return &model.Role{Project: e.Project, Name: e.Name}
+	case *model.Permission:
+		return &model.Permission{Project: e.Project, Name: e.Name}
+	case *model.Project:
+		return &model.Project{Name: e.Name}
+	case *model.Target:
+		return &model.Target{Name: e.Name}
+	case *model.Env:
+		return &model.Env{Name: e.Name}
+	default:
+		// the unknown entity is copied, all fields are overwritten by the stored one
+		return reflect.New(reflect.TypeOf(entity).Elem()).Interface().(datastore.Entity)
+	}
+}
+
+func (u systemInfoUsecaseImpl) restoreDexConfig(ctx context.Context, dexConfig *model.DexConfig) error {
+	// the config of the secret is replaced by the restored one, so the address only matters when creating the secret
+	secret, err := initDexConfig(ctx, u.kubeClient, strings.TrimSuffix(dexConfig.Issuer, "/dex"))
+	if err != nil {
+		return err
+	}
+	config, err := model.NewJSONStructByStruct(dexConfig)
+	if err != nil {
+		return err
+	}
+	c, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(secret.Data[secretDexConfigKey], c) {
+		return nil
+	}
+	secret.Data[secretDexConfigKey] = c
+	if err := u.kubeClient.Update(ctx, secret); err != nil {
+		return err
+	}
+	if err := restartDex(ctx, u.kubeClient); err != nil {
+		log.Logger.Errorf("failed to restart the dex: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
+)
+
+var _ = Describe("Test system config backup and restore", func() {
+	var (
+		sysUsecase *systemInfoUsecaseImpl
+		ds         datastore.DataStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		ds, err = NewDatastore(datastore.Config{Type: "kubeapi", Database: "backup-test-" + strconv.FormatInt(time.Now().UnixNano(), 10)})
+		Expect(ds).ToNot(BeNil())
+		Expect(err).Should(BeNil())
+		sysUsecase = &systemInfoUsecaseImpl{ds: ds, kubeClient: k8sClient}
+	})
+
+	It("Test export and import the system config", func() {
+		Expect(ds.Add(context.TODO(), &model.User{Name: "backup-user", Email: "backup@test.com", Password: "hashed"})).Should(Succeed())
+		Expect(ds.Add(context.TODO(), &model.Project{Name: "backup-project", Owner: "backup-user"})).Should(Succeed())
+		Expect(ds.Add(context.TODO(), &model.Target{Name: "backup-target", Project: "backup-project"})).Should(Succeed())
+
+		By("the secrets are excluded by default")
+		bundle, err := sysUsecase.ExportSystemConfig(context.TODO(), false)
+		Expect(err).Should(BeNil())
+		Expect(bundle.Version).Should(Equal(SystemConfigBundleVersion))
+		Expect(bundle.SystemInfo).ShouldNot(BeNil())
+		Expect(len(bundle.Users)).Should(Equal(1))
+		Expect(bundle.Users[0].Password).Should(BeEmpty())
+		Expect(len(bundle.Projects)).Should(Equal(1))
+		Expect(len(bundle.Targets)).Should(Equal(1))
+
+		bundleWithSecrets, err := sysUsecase.ExportSystemConfig(context.TODO(), true)
+		Expect(err).Should(BeNil())
+		Expect(bundleWithSecrets.Users[0].Password).Should(Equal("hashed"))
+
+		By("restore the bundle into a fresh datastore")
+		freshDS, err := NewDatastore(datastore.Config{Type: "kubeapi", Database: "backup-restore-" + strconv.FormatInt(time.Now().UnixNano(), 10)})
+		Expect(err).Should(BeNil())
+		freshUsecase := &systemInfoUsecaseImpl{ds: freshDS, kubeClient: k8sClient}
+		bundleWithSecrets.DexConfig = nil
+		bundleWithSecrets.Integrations = nil
+		resp, err := freshUsecase.ImportSystemConfig(context.TODO(), *bundleWithSecrets)
+		Expect(err).Should(BeNil())
+		Expect(resp.Imported["users"]).Should(Equal(1))
+		Expect(resp.Imported["projects"]).Should(Equal(1))
+		Expect(resp.Imported["targets"]).Should(Equal(1))
+
+		user := &model.User{Name: "backup-user"}
+		Expect(freshDS.Get(context.TODO(), user)).Should(Succeed())
+		Expect(user.Password).Should(Equal("hashed"))
+
+		By("the existing password is kept when the bundle excludes the secrets")
+		bundle.DexConfig = nil
+		bundle.Integrations = nil
+		_, err = freshUsecase.ImportSystemConfig(context.TODO(), *bundle)
+		Expect(err).Should(BeNil())
+		Expect(freshDS.Get(context.TODO(), user)).Should(Succeed())
+		Expect(user.Password).Should(Equal("hashed"))
+
+		By("the unknown version is rejected")
+		bundle.Version = "v0"
+		_, err = freshUsecase.ImportSystemConfig(context.TODO(), *bundle)
+		Expect(err).Should(Equal(bcode.ErrUnsupportedBundleVersion))
+	})
+	It("Test the invalid bundle is rejected before any write", func() {
+		bundle, err := sysUsecase.ExportSystemConfig(context.TODO(), false)
+		Expect(err).Should(BeNil())
+		bundle.Users = []*model.User{{Name: "invalid-bundle-user", Email: "invalid@test.com"}}
+		bundle.Projects = []*model.Project{{Name: ""}}
+		_, err = sysUsecase.ImportSystemConfig(context.TODO(), *bundle)
+		Expect(err).ShouldNot(BeNil())
+		Expect(err.(*bcode.Bcode).BusinessCode).Should(Equal(bcode.ErrInvalidBundle.BusinessCode))
+		exist, err := ds.IsExist(context.TODO(), &model.User{Name: "invalid-bundle-user"})
+		Expect(err).Should(BeNil())
+		Expect(exist).Should(BeFalse())
+	})
+
+	It("Test restore the previous state after a failed import", func() {
+		Expect(ds.Add(context.TODO(), &model.Project{Name: "rollback-project", Alias: "before"})).Should(Succeed())
+		rollback := &importRollback{ds: ds, kubeClient: k8sClient}
+		Expect(rollback.upsert(context.TODO(), &model.Project{Name: "rollback-project", Alias: "after"})).Should(Succeed())
+		Expect(rollback.upsert(context.TODO(), &model.User{Name: "rollback-user", Email: "rollback@test.com"})).Should(Succeed())
+
+		rollback.restore(context.TODO())
+		project := &model.Project{Name: "rollback-project"}
+		Expect(ds.Get(context.TODO(), project)).Should(Succeed())
+		Expect(project.Alias).Should(Equal("before"))
+		exist, err := ds.IsExist(context.TODO(), &model.User{Name: "rollback-user"})
+		Expect(err).Should(BeNil())
+		Expect(exist).Should(BeFalse())
+	})
+})
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/version"
+)
+
+var _ = Describe("Test system info usecase functions", func() {
+	var (
+		sysUsecase *systemInfoUsecaseImpl
+		ds         datastore.DataStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		ds, err = NewDatastore(datastore.Config{Type: "kubeapi", Database: "system-info-test-" + strconv.FormatInt(time.Now().UnixNano(), 10)})
+		Expect(ds).ToNot(BeNil())
+		Expect(err).Should(BeNil())
+		sysUsecase = &systemInfoUsecaseImpl{ds: ds, kubeClient: k8sClient}
+	})
+
+	It("Test the collection preview follows the collection config", func() {
+		info, err := sysUsecase.Get(context.TODO())
+		Expect(err).Should(BeNil())
+		info.StatisticInfo = model.StatisticInfo{AppCount: "<10", ClusterCount: "<3", TopKCompDef: []string{"webservice"}}
+		Expect(ds.Put(context.TODO(), info)).Should(Succeed())
+
+		By("all categories are collected by default")
+		preview, err := sysUsecase.GetCollectionPreview(context.TODO())
+		Expect(err).Should(BeNil())
+		Expect(preview.EnableCollection).Should(BeTrue())
+		Expect(preview.Payload.SystemVersion).ShouldNot(BeNil())
+		Expect(preview.Payload.StatisticInfo.AppCount).Should(Equal("<10"))
+		Expect(preview.Payload.StatisticInfo.ComponentDefinitionTopList).Should(Equal([]string{"webservice"}))
+
+		_, err = sysUsecase.UpdateSystemInfo(context.TODO(), apisv1.SystemInfoRequest{
+			EnableCollection: true,
+			LoginType:        model.LoginTypeLocal,
+			CollectionConfig: &model.CollectionConfig{UsageStatistics: true, ExportOnly: true},
+		})
+		Expect(err).Should(BeNil())
+		preview, err = sysUsecase.GetCollectionPreview(context.TODO())
+		Expect(err).Should(BeNil())
+		Expect(preview.ExportOnly).Should(BeTrue())
+		Expect(preview.Payload.SystemVersion).Should(BeNil())
+		Expect(preview.Payload.StatisticInfo.AppCount).Should(Equal("<10"))
+		Expect(preview.Payload.StatisticInfo.ComponentDefinitionTopList).Should(BeEmpty())
+
+		By("the displayed system info does not follow the collection config")
+		resp, err := sysUsecase.GetSystemInfo(context.TODO())
+		Expect(err).Should(BeNil())
+		Expect(resp.PlatformID).Should(Equal(info.InstallID))
+		Expect(resp.SystemVersion.VelaVersion).Should(Equal(version.VelaVersion))
+		Expect(resp.StatisticInfo.AppCount).Should(Equal("<10"))
+		Expect(resp.StatisticInfo.ComponentDefinitionTopList).Should(Equal([]string{"webservice"}))
+
+		By("nothing is collected when the collection is disabled")
+		_, err = sysUsecase.UpdateSystemInfo(context.TODO(), apisv1.SystemInfoRequest{
+			EnableCollection: false,
+			LoginType:        model.LoginTypeLocal,
+		})
+		Expect(err).Should(BeNil())
+		preview, err = sysUsecase.GetCollectionPreview(context.TODO())
+		Expect(err).Should(BeNil())
+		Expect(preview.Payload.StatisticInfo).Should(BeNil())
+		Expect(preview.Payload.SystemVersion).Should(BeNil())
+		resp, err = sysUsecase.GetSystemInfo(context.TODO())
+		Expect(err).Should(BeNil())
+		Expect(resp.EnableCollection).Should(BeFalse())
+		Expect(resp.PlatformID).Should(Equal(info.InstallID))
+		Expect(resp.StatisticInfo.ClusterCount).Should(Equal("<3"))
+	})
+
+	It("Test the cached system info is invalidated by the update", func() {
+		cachedUsecase := &systemInfoUsecaseImpl{ds: ds, kubeClient: k8sClient, cache: NewCache(context.TODO(), true)}
+		info, err := cachedUsecase.Get(context.TODO())
+		Expect(err).Should(BeNil())
+		Expect(info.LoginType).Should(Equal(model.LoginTypeLocal))
+
+		By("the returned system info does not modify the cached one")
+		info.LoginType = model.LoginTypeDex
+		info, err = cachedUsecase.Get(context.TODO())
+		Expect(err).Should(BeNil())
+		Expect(info.LoginType).Should(Equal(model.LoginTypeLocal))
+
+		_, err = cachedUsecase.UpdateSystemInfo(context.TODO(), apisv1.SystemInfoRequest{
+			EnableCollection: true,
+			LoginType:        model.LoginTypeLocal,
+			VelaAddress:      "http://velaux.example.com",
+		})
+		Expect(err).Should(BeNil())
+		info, err = cachedUsecase.Get(context.TODO())
+		Expect(err).Should(BeNil())
+		Expect(info.VelaAddress).Should(Equal("http://velaux.example.com"))
+	})
+})
@@ -39,7 +39,7 @@ type TargetUsecase interface {
 	DeleteTarget(ctx context.Context, TargetName string) error
 	CreateTarget(ctx context.Context, req apisv1.CreateTargetRequest) (*apisv1.DetailTargetResponse, error)
 	UpdateTarget(ctx context.Context, Target *model.Target, req apisv1.UpdateTargetRequest) (*apisv1.DetailTargetResponse, error)
-	ListTargets(ctx context.Context, page, pageSize int, projectName string) (*apisv1.ListTargetResponse, error)
+	ListTargets(ctx context.Context, page, pageSize int, listOptions apisv1.ListTargetOptions) (*apisv1.ListTargetResponse, error)
 	ListTargetCount(ctx context.Context, projectName string) (int64, error)
 	Init(ctx context.Context) error
 }
@@ -80,11 +80,20 @@ func (dt *targetUsecaseImpl) Init(ctx context.Context) error {
 	}
 	return nil
 }
-func (dt *targetUsecaseImpl) ListTargets(ctx context.Context, page, pageSize int, projectName string) (*apisv1.ListTargetResponse, error) {
-	targets, err := listTarget(ctx, dt.ds, projectName, &datastore.ListOptions{
-		Page:     page,
-		PageSize: pageSize,
-		SortBy:   []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}},
+func (dt *targetUsecaseImpl) ListTargets(ctx context.Context, page, pageSize int, listOptions apisv1.ListTargetOptions) (*apisv1.ListTargetResponse, error) {
+	fo := datastore.FilterOptions{Equals: convertEqualFilters(listOptions.Filters)}
+	if listOptions.Query != "" {
+		fo.Keywords = append(fo.Keywords, datastore.KeywordQueryOption{
+			Keys:    []string{"name", "alias", "description"},
+			Keyword: listOptions.Query,
+		})
+	}
+	sortBy := convertSortOptions(listOptions.SortBy, datastore.SortOption{Key: "createTime", Order: datastore.SortOrderDescending})
+	targets, err := listTarget(ctx, dt.ds, listOptions.Project, &datastore.ListOptions{
+		FilterOptions: fo,
+		Page:          page,
+		PageSize:      pageSize,
+		SortBy:        sortBy,
 	})
 	if err != nil {
 		return nil, err
@@ -95,7 +104,7 @@ func (dt *targetUsecaseImpl) ListTargets(ctx context.Context, page, pageSize int
 	for _, raw := range targets {
 		resp.Targets = append(resp.Targets, *(dt.convertFromTargetModel(ctx, raw)))
 	}
-	count, err := dt.ds.Count(ctx, &model.Target{Project: projectName}, nil)
+	count, err := dt.ds.Count(ctx, &model.Target{Project: listOptions.Project}, &fo)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,245 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/log"
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
+)
+
+const (
+	// APITokenPrefix is the prefix of the api token, it is used to distinguish the api token from the JWT token
+	APITokenPrefix = "vela_"
+	// lastUsedTimeInterval avoids updating the datastore on every request
+	lastUsedTimeInterval = time.Minute
+)
+
+// TokenUsecase manage the api tokens of the automation clients
+type TokenUsecase interface {
+	CreateAPIToken(ctx context.Context, req apisv1.CreateAPITokenRequest) (*apisv1.CreateAPITokenResponse, error)
+	ListAPITokens(ctx context.Context) (*apisv1.ListAPITokenResponse, error)
+	DeleteAPIToken(ctx context.Context, tokenID string) error
+	ValidateAPIToken(ctx context.Context, token string) (*model.APIToken, error)
+}
+
+type tokenUsecaseImpl struct {
+	ds datastore.DataStore
+}
+
+// NewTokenUsecase new token usecase
+func NewTokenUsecase(ds datastore.DataStore) TokenUsecase {
+	return &tokenUsecaseImpl{ds: ds}
+}
+
+// CreateAPIToken create an api token bound to the login user
+func (t *tokenUsecaseImpl) CreateAPIToken(ctx context.Context, req apisv1.CreateAPITokenRequest) (*apisv1.CreateAPITokenResponse, error) {
+	userName, ok := ctx.Value(&apisv1.CtxKeyUser).(string)
+	if !ok {
+		return nil, bcode.ErrUnauthorized
+	}
+	if req.Scope == model.APITokenScopeProject {
+		if len(req.Projects) == 0 {
+			return nil, bcode.ErrAPITokenInvalidProjects
+		}
+		for _, name := range req.Projects {
+			if err := t.ds.Get(ctx, &model.Project{Name: name}); err != nil {
+				if errors.Is(err, datastore.ErrRecordNotExist) {
+					return nil, bcode.ErrAPITokenInvalidProjects
+				}
+				return nil, err
+			}
+		}
+	}
+	secret, err := generateTokenSecret()
+	if err != nil {
+		return nil, err
+	}
+	token := &model.APIToken{
+		ID:          utilrand.String(16),
+		Name:        req.Name,
+		Username:    userName,
+		Scope:       req.Scope,
+		Description: req.Description,
+		SecretHash:  hashTokenSecret(secret),
+	}
+	if req.Scope == model.APITokenScopeProject {
+		token.Projects = req.Projects
+	}
+	if req.ExpireDays > 0 {
+		token.ExpireTime = time.Now().AddDate(0, 0, req.ExpireDays)
+	}
+	if err := t.ds.Add(ctx, token); err != nil {
+		return nil, err
+	}
+	return &apisv1.CreateAPITokenResponse{
+		APITokenBase: *convertAPITokenBase(token),
+		Token:        fmt.Sprintf("%s%s_%s", APITokenPrefix, token.ID, secret),
+	}, nil
+}
+
+// ListAPITokens list the api tokens of the login user
+func (t *tokenUsecaseImpl) ListAPITokens(ctx context.Context) (*apisv1.ListAPITokenResponse, error) {
+	userName, ok := ctx.Value(&apisv1.CtxKeyUser).(string)
+	if !ok {
+		return nil, bcode.ErrUnauthorized
+	}
+	entities, err := t.ds.List(ctx, &model.APIToken{Username: userName}, &datastore.ListOptions{
+		SortBy: []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &apisv1.ListAPITokenResponse{Tokens: []*apisv1.APITokenBase{}}
+	for _, entity := range entities {
+		resp.Tokens = append(resp.Tokens, convertAPITokenBase(entity.(*model.APIToken)))
+	}
+	return resp, nil
+}
+
+// DeleteAPIToken revoke an api token of the login user
+func (t *tokenUsecaseImpl) DeleteAPIToken(ctx context.Context, tokenID string) error {
+	userName, ok := ctx.Value(&apisv1.CtxKeyUser).(string)
+	if !ok {
+		return bcode.ErrUnauthorized
+	}
+	token := &model.APIToken{ID: tokenID}
+	if err := t.ds.Get(ctx, token); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return bcode.ErrAPITokenNotExist
+		}
+		return err
+	}
+	if token.Username != userName {
+		return bcode.ErrAPITokenNotExist
+	}
+	return t.ds.Delete(ctx, token)
+}
+
+// ValidateAPIToken checks the api token and returns the stored token
+func (t *tokenUsecaseImpl) ValidateAPIToken(ctx context.Context, tokenString string) (*model.APIToken, error) {
+	id, secret, ok := parseAPIToken(tokenString)
+	if !ok {
+		return nil, bcode.ErrTokenInvalid
+	}
+	token := &model.APIToken{ID: id}
+	if err := t.ds.Get(ctx, token); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrTokenInvalid
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(token.SecretHash), []byte(hashTokenSecret(secret))) != 1 {
+		return nil, bcode.ErrTokenInvalid
+	}
+	if token.IsExpired() {
+		return nil, bcode.ErrAPITokenExpired
+	}
+	user := &model.User{Name: token.Username}
+	if err := t.ds.Get(ctx, user); err != nil {
+		if errors.Is(err, datastore.ErrRecordNotExist) {
+			return nil, bcode.ErrTokenInvalid
+		}
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, bcode.ErrUserAlreadyDisabled
+	}
+	if time.Since(token.LastUsedTime) > lastUsedTimeInterval {
+		token.LastUsedTime = time.Now()
+		if err := t.ds.Put(ctx, token); err != nil {
+			log.Logger.Warnf("failed to update the last used time of the api token %s: %s", token.ID, err.Error())
+		}
+	}
+	return token, nil
+}
+
+// APITokenScopeCheckedAttribute is the request attribute set after the project scope of the api token is checked,
+// the project scoped token is rejected by the routes without the check.
+const APITokenScopeCheckedAttribute = "apiTokenScopeChecked"
+
+// IsProjectScopedAPIToken checks whether the request is authenticated by a project scoped api token
+func IsProjectScopedAPIToken(ctx context.Context) bool {
+	token, ok := ctx.Value(&apisv1.CtxKeyAPIToken).(*model.APIToken)
+	return ok && token.Scope == model.APITokenScopeProject
+}
+
+// CheckAPITokenProject checks whether the request of the project is allowed by the api token used by the request
+func CheckAPITokenProject(ctx context.Context, projectName string) bool {
+	token, ok := ctx.Value(&apisv1.CtxKeyAPIToken).(*model.APIToken)
+	if !ok || token.Scope != model.APITokenScopeProject {
+		return true
+	}
+	for _, project := range token.Projects {
+		if project == projectName {
+			return true
+		}
+	}
+	return false
+}
+
+func parseAPIToken(token string) (id, secret string, ok bool) {
+	if !strings.HasPrefix(token, APITokenPrefix) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(token, APITokenPrefix), "_", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+func generateTokenSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashTokenSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func convertAPITokenBase(token *model.APIToken) *apisv1.APITokenBase {
+	return &apisv1.APITokenBase{
+		ID:           token.ID,
+		Name:         token.Name,
+		Username:     token.Username,
+		Description:  token.Description,
+		Scope:        token.Scope,
+		Projects:     token.Projects,
+		CreateTime:   token.CreateTime,
+		ExpireTime:   token.ExpireTime,
+		LastUsedTime: token.LastUsedTime,
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
+)
+
+var _ = Describe("Test token usecase functions", func() {
+	var (
+		tokenUsecase *tokenUsecaseImpl
+		ds           datastore.DataStore
+		ctx          context.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+		ds, err = NewDatastore(datastore.Config{Type: "kubeapi", Database: "token-test-" + strconv.FormatInt(time.Now().UnixNano(), 10)})
+		Expect(ds).ToNot(BeNil())
+		Expect(err).Should(BeNil())
+		tokenUsecase = &tokenUsecaseImpl{ds: ds}
+		ctx = context.WithValue(context.TODO(), &apisv1.CtxKeyUser, "token-user")
+		Expect(ds.Add(context.TODO(), &model.User{Name: "token-user", Email: "token@test.com"})).Should(Succeed())
+	})
+
+	It("Test create, validate and delete the api token", func() {
+		resp, err := tokenUsecase.CreateAPIToken(ctx, apisv1.CreateAPITokenRequest{
+			Name:  "ci",
+			Scope: model.APITokenScopeReadOnly,
+		})
+		Expect(err).Should(BeNil())
+		Expect(strings.HasPrefix(resp.Token, APITokenPrefix)).Should(BeTrue())
+		Expect(resp.Username).Should(Equal("token-user"))
+
+		stored := &model.APIToken{ID: resp.ID}
+		Expect(ds.Get(context.TODO(), stored)).Should(Succeed())
+		Expect(stored.SecretHash).ShouldNot(BeEmpty())
+		Expect(strings.Contains(resp.Token, stored.SecretHash)).Should(BeFalse())
+
+		token, err := tokenUsecase.ValidateAPIToken(context.TODO(), resp.Token)
+		Expect(err).Should(BeNil())
+		Expect(token.Scope).Should(Equal(model.APITokenScopeReadOnly))
+
+		_, err = tokenUsecase.ValidateAPIToken(context.TODO(), resp.Token+"x")
+		Expect(err).Should(Equal(bcode.ErrTokenInvalid))
+
+		list, err := tokenUsecase.ListAPITokens(ctx)
+		Expect(err).Should(BeNil())
+		Expect(len(list.Tokens)).Should(Equal(1))
+
+		By("the token can only be deleted by the owner")
+		otherCtx := context.WithValue(context.TODO(), &apisv1.CtxKeyUser, "other-user")
+		Expect(tokenUsecase.DeleteAPIToken(otherCtx, resp.ID)).Should(Equal(bcode.ErrAPITokenNotExist))
+		Expect(tokenUsecase.DeleteAPIToken(ctx, resp.ID)).Should(Succeed())
+		_, err = tokenUsecase.ValidateAPIToken(context.TODO(), resp.Token)
+		Expect(err).Should(Equal(bcode.ErrTokenInvalid))
+	})
+
+	It("Test the expired token", func() {
+		resp, err := tokenUsecase.CreateAPIToken(ctx, apisv1.CreateAPITokenRequest{
+			Name:       "expired",
+			Scope:      model.APITokenScopeAdmin,
+			ExpireDays: 1,
+		})
+		Expect(err).Should(BeNil())
+		stored := &model.APIToken{ID: resp.ID}
+		Expect(ds.Get(context.TODO(), stored)).Should(Succeed())
+		stored.ExpireTime = time.Now().Add(-time.Minute)
+		Expect(ds.Put(context.TODO(), stored)).Should(Succeed())
+		_, err = tokenUsecase.ValidateAPIToken(context.TODO(), resp.Token)
+		Expect(err).Should(Equal(bcode.ErrAPITokenExpired))
+	})
+
+	It("Test the project scoped token", func() {
+		_, err := tokenUsecase.CreateAPIToken(ctx, apisv1.CreateAPITokenRequest{
+			Name:     "project",
+			Scope:    model.APITokenScopeProject,
+			Projects: []string{"not-exist"},
+		})
+		Expect(err).Should(Equal(bcode.ErrAPITokenInvalidProjects))
+
+		Expect(ds.Add(context.TODO(), &model.Project{Name: "token-project"})).Should(Succeed())
+		resp, err := tokenUsecase.CreateAPIToken(ctx, apisv1.CreateAPITokenRequest{
+			Name:     "project",
+			Scope:    model.APITokenScopeProject,
+			Projects: []string{"token-project"},
+		})
+		Expect(err).Should(BeNil())
+		token, err := tokenUsecase.ValidateAPIToken(context.TODO(), resp.Token)
+		Expect(err).Should(BeNil())
+
+		tokenCtx := context.WithValue(context.TODO(), &apisv1.CtxKeyAPIToken, token)
+		Expect(CheckAPITokenProject(tokenCtx, "token-project")).Should(BeTrue())
+		Expect(CheckAPITokenProject(tokenCtx, "other-project")).Should(BeFalse())
+		Expect(CheckAPITokenProject(tokenCtx, "")).Should(BeFalse())
+		Expect(CheckAPITokenProject(context.TODO(), "other-project")).Should(BeTrue())
+	})
+})
@@ -53,25 +53,27 @@ type UserUsecase interface {
 }
 
 type userUsecaseImpl struct {
-	ds             datastore.DataStore
-	k8sClient      client.Client
-	projectUsecase ProjectUsecase
-	rbacUsecase    RBACUsecase
-	sysUsecase     SystemInfoUsecase
+	ds                  datastore.DataStore
+	k8sClient           client.Client
+	projectUsecase      ProjectUsecase
+	rbacUsecase         RBACUsecase
+	sysUsecase          SystemInfoUsecase
+	notificationUsecase NotificationUsecase
 }
 
 // NewUserUsecase new User usecase
-func NewUserUsecase(ds datastore.DataStore, projectUsecase ProjectUsecase, sysUsecase SystemInfoUsecase, rbacUsecase RBACUsecase) UserUsecase {
+func NewUserUsecase(ds datastore.DataStore, projectUsecase ProjectUsecase, sysUsecase SystemInfoUsecase, rbacUsecase RBACUsecase, notificationUsecase NotificationUsecase) UserUsecase {
 	k8sClient, err := clients.GetKubeClient()
 	if err != nil {
 		log.Logger.Fatalf("get k8sClient failure: %s", err.Error())
 	}
 	return &userUsecaseImpl{
-		k8sClient:      k8sClient,
-		ds:             ds,
-		projectUsecase: projectUsecase,
-		sysUsecase:     sysUsecase,
-		rbacUsecase:    rbacUsecase,
+		k8sClient:           k8sClient,
+		ds:                  ds,
+		projectUsecase:      projectUsecase,
+		sysUsecase:          sysUsecase,
+		rbacUsecase:         rbacUsecase,
+		notificationUsecase: notificationUsecase,
 	}
 }
 
@@ -160,6 +162,15 @@ func (u *userUsecaseImpl) DeleteUser(ctx context.Context, username string) error
 			log.Logger.Errorf("failed to delete project user %s: %s", pu.PrimaryKey(), err.Error())
 		}
 	}
+	tokens, err := u.ds.List(ctx, &model.APIToken{Username: username}, &datastore.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		if err := u.ds.Delete(ctx, token); err != nil {
+			log.Logger.Errorf("failed to delete api token %s: %s", token.PrimaryKey(), err.Error())
+		}
+	}
 	if err := u.ds.Delete(ctx, &model.User{Name: username}); err != nil {
 		log.Logger.Errorf("failed to delete user %s %v", utils2.Sanitize(username), err.Error())
 		return err
@@ -173,7 +184,7 @@ func (u *userUsecaseImpl) CreateUser(ctx context.Context, req apisv1.CreateUserR
 	if err != nil {
 		return nil, err
 	}
-	if sysInfo.LoginType == model.LoginTypeDex {
+	if sysInfo.LoginType == model.LoginTypeDex || sysInfo.LoginType == model.LoginTypeOIDC {
 		return nil, bcode.ErrUserCannotModified
 	}
 	hash, err := GeneratePasswordHash(req.Password)
@@ -192,6 +203,7 @@ func (u *userUsecaseImpl) CreateUser(ctx context.Context, req apisv1.CreateUserR
 	if err := u.ds.Add(ctx, user); err != nil {
 		return nil, err
 	}
+	emitNotification(ctx, u.notificationUsecase, model.EventUserCreated, map[string]string{"name": user.Name, "email": user.Email, "alias": user.Alias})
 	return convertUserBase(user), nil
 }
 
@@ -201,7 +213,7 @@ func (u *userUsecaseImpl) UpdateUser(ctx context.Context, user *model.User, req
 	if err != nil {
 		return nil, err
 	}
-	if sysInfo.LoginType == model.LoginTypeDex {
+	if sysInfo.LoginType == model.LoginTypeDex || sysInfo.LoginType == model.LoginTypeOIDC {
 		return nil, bcode.ErrUserCannotModified
 	}
 	if req.Alias != "" {
@@ -256,13 +268,20 @@ func (u *userUsecaseImpl) ListUsers(ctx context.Context, page, pageSize int, lis
 	if listOptions.Alias != "" {
 		queries = append(queries, datastore.FuzzyQueryOption{Key: "alias", Query: listOptions.Alias})
 	}
-	fo := datastore.FilterOptions{Queries: queries}
+	fo := datastore.FilterOptions{Queries: queries, Equals: convertEqualFilters(listOptions.Filters, "disabled")}
+	if listOptions.Query != "" {
+		fo.Keywords = append(fo.Keywords, datastore.KeywordQueryOption{
+			Keys:    []string{"name", "alias", "email"},
+			Keyword: listOptions.Query,
+		})
+	}
+	sortBy := convertSortOptions(listOptions.SortBy, datastore.SortOption{Key: "createTime", Order: datastore.SortOrderDescending})
 
 	var userList []*apisv1.DetailUserResponse
 	users, err := u.ds.List(ctx, user, &datastore.ListOptions{
 		Page:          page,
 		PageSize:      pageSize,
-		SortBy:        []datastore.SortOption{{Key: "createTime", Order: datastore.SortOrderDescending}},
+		SortBy:        sortBy,
 		FilterOptions: fo,
 	})
 	if err != nil {
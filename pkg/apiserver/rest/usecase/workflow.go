@@ -65,24 +65,26 @@ type WorkflowUsecase interface {
 }
 
 // NewWorkflowUsecase new workflow usecase
-func NewWorkflowUsecase(ds datastore.DataStore, envUsecase EnvUsecase) WorkflowUsecase {
+func NewWorkflowUsecase(ds datastore.DataStore, envUsecase EnvUsecase, notificationUsecase NotificationUsecase) WorkflowUsecase {
 	kubecli, err := clients.GetKubeClient()
 	if err != nil {
 		log.Logger.Fatalf("get kubeclient failure %s", err.Error())
 	}
 	return &workflowUsecaseImpl{
-		ds:         ds,
-		kubeClient: kubecli,
-		apply:      apply.NewAPIApplicator(kubecli),
-		envUsecase: envUsecase,
+		ds:                  ds,
+		kubeClient:          kubecli,
+		apply:               apply.NewAPIApplicator(kubecli),
+		envUsecase:          envUsecase,
+		notificationUsecase: notificationUsecase,
 	}
 }
 
 type workflowUsecaseImpl struct {
-	ds         datastore.DataStore
-	kubeClient client.Client
-	apply      apply.Applicator
-	envUsecase EnvUsecase
+	ds                  datastore.DataStore
+	kubeClient          client.Client
+	apply               apply.Applicator
+	envUsecase          EnvUsecase
+	notificationUsecase NotificationUsecase
 }
 
 // DeleteWorkflow delete application workflow
@@ -420,6 +422,7 @@ func (w *workflowUsecaseImpl) syncWorkflowStatus(ctx context.Context, app *v1bet
 	}
 
 	if app.Status.Workflow != nil {
+		previousStatus, previousFinished := record.Status, record.Finished
 		status := app.Status.Workflow
 		summaryStatus := model.RevisionStatusRunning
 		if status.Finished {
@@ -453,6 +456,14 @@ func (w *workflowUsecaseImpl) syncWorkflowStatus(ctx context.Context, app *v1bet
 		if err := w.ds.Put(ctx, revision); err != nil {
 			return err
 		}
+
+		// the terminated workflow is not finished, so the events are only emitted when the status changes
+		switch {
+		case record.Status == model.RevisionStatusTerminated && previousStatus != model.RevisionStatusTerminated:
+			emitWorkflowEvent(ctx, w.notificationUsecase, model.EventWorkflowFailed, record)
+		case record.Status == model.RevisionStatusComplete && previousFinished != "true":
+			emitWorkflowEvent(ctx, w.notificationUsecase, model.EventWorkflowSucceeded, record)
+		}
 	}
 
 	if record.Finished == "true" {
@@ -462,6 +473,16 @@ func (w *workflowUsecaseImpl) syncWorkflowStatus(ctx context.Context, app *v1bet
 	return nil
 }
 
+func emitWorkflowEvent(ctx context.Context, n NotificationUsecase, event string, record *model.WorkflowRecord) {
+	emitNotification(ctx, n, event, map[string]string{
+		"application": record.AppPrimaryKey,
+		"workflow":    record.WorkflowName,
+		"record":      record.Name,
+		"revision":    record.RevisionPrimaryKey,
+		"status":      record.Status,
+	})
+}
+
 func (w *workflowUsecaseImpl) CreateWorkflowRecord(ctx context.Context, appModel *model.Application, app *v1beta1.Application, workflow *model.Workflow) error {
 	if app.Annotations == nil {
 		return fmt.Errorf("empty annotations in application")
@@ -496,14 +517,16 @@ func (w *workflowUsecaseImpl) CreateWorkflowRecord(ctx context.Context, appModel
 		return err
 	}
 
-	if err := resetRevisionsAndRecords(ctx, w.ds, appModel.PrimaryKey(), workflow.Name, app.Annotations[oam.AnnotationDeployVersion], app.Annotations[oam.AnnotationPublishVersion]); err != nil {
+	if err := resetRevisionsAndRecords(ctx, w.ds, w.notificationUsecase, appModel.PrimaryKey(), workflow.Name, app.Annotations[oam.AnnotationDeployVersion], app.Annotations[oam.AnnotationPublishVersion]); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func resetRevisionsAndRecords(ctx context.Context, ds datastore.DataStore, appName, workflowName, skipRevision, skipRecord string) error {
+// resetRevisionsAndRecords terminates the running revisions and the unfinished workflow records except the skipped ones,
+// the workflow.failed event is emitted for every terminated record.
+func resetRevisionsAndRecords(ctx context.Context, ds datastore.DataStore, n NotificationUsecase, appName, workflowName, skipRevision, skipRecord string) error {
 	// set revision status' status to terminate
 	var revision = model.ApplicationRevision{
 		AppPrimaryKey: appName,
@@ -553,7 +576,9 @@ func resetRevisionsAndRecords(ctx context.Context, ds datastore.DataStore, appNa
 			}
 			if err := ds.Put(ctx, record); err != nil {
 				klog.Info("failed to set rest records' status to terminate", "app name", appName, "workflow name", record.WorkflowName, "record name", record.Name, "error", err)
+				continue
 			}
+			emitWorkflowEvent(ctx, n, model.EventWorkflowFailed, record)
 		}
 	}
 
@@ -689,6 +714,14 @@ func (w *workflowUsecaseImpl) RollbackRecord(ctx context.Context, appModel *mode
 		return err
 	}
 
+	emitNotification(ctx, w.notificationUsecase, model.EventApplicationRolledBack, map[string]string{
+		"application":      appModel.Name,
+		"project":          appModel.Project,
+		"workflow":         workflow.Name,
+		"envName":          workflow.EnvName,
+		"revision":         revisionVersion,
+		"originalRevision": originalRevision.Version,
+	})
 	return nil
 }
 
@@ -425,6 +425,34 @@ var _ = Describe("Test workflow usecase functions", func() {
 		Expect(record.Status).Should(Equal(model.RevisionStatusTerminated))
 	})
 
+	It("Test the terminated workflow emits the failed event once", func() {
+		ctx := context.TODO()
+		notification := &fakeNotificationUsecase{}
+		workflowUsecase.notificationUsecase = notification
+
+		_, err := envUsecase.CreateEnv(ctx, apisv1.CreateEnvRequest{Name: "terminate-event"})
+		Expect(err).Should(BeNil())
+		workflow := &model.Workflow{Name: "terminate-event-workflow", EnvName: "terminate-event"}
+		app, err := createTestSuspendApp(ctx, appName, "terminate-event", "revision-terminate-event", workflow.Name, "test-workflow-event-1", workflowUsecase.kubeClient)
+		Expect(err).Should(BeNil())
+		Expect(workflowUsecase.CreateWorkflowRecord(ctx, &model.Application{Name: appName}, app, workflow)).Should(BeNil())
+		Expect(workflowUsecase.createTestApplicationRevision(ctx, &model.ApplicationRevision{
+			AppPrimaryKey: appName,
+			Version:       "revision-terminate-event",
+			Status:        model.RevisionStatusRunning,
+		})).Should(BeNil())
+
+		// terminating the workflow by the api does not finish it
+		app.Status.Workflow.Terminated = true
+		app.Status.Workflow.Finished = false
+		Expect(workflowUsecase.syncWorkflowStatus(ctx, app, "test-workflow-event-1", app.Name)).Should(BeNil())
+		Expect(notification.events).Should(Equal([]string{model.EventWorkflowFailed}))
+
+		// the unfinished record is synced again, but the event is not emitted again
+		Expect(workflowUsecase.syncWorkflowStatus(ctx, app, "test-workflow-event-1", app.Name)).Should(BeNil())
+		Expect(notification.events).Should(Equal([]string{model.EventWorkflowFailed}))
+	})
+
 	It("Test RollbackRecord function", func() {
 		ctx := context.TODO()
 		_, err := envUsecase.CreateEnv(context.TODO(), apisv1.CreateEnvRequest{Name: "rollback"})
@@ -531,8 +559,10 @@ var _ = Describe("Test workflow usecase functions", func() {
 		})
 		Expect(err).Should(BeNil())
 
-		err = resetRevisionsAndRecords(ctx, workflowUsecase.ds, "reset-app", "reset-workflow", "", "")
+		notification := &fakeNotificationUsecase{}
+		err = resetRevisionsAndRecords(ctx, workflowUsecase.ds, notification, "reset-app", "reset-workflow", "", "")
 		Expect(err).Should(BeNil())
+		Expect(notification.events).Should(Equal([]string{model.EventWorkflowFailed}))
 
 		record := &model.WorkflowRecord{
 			AppPrimaryKey: "reset-app",
@@ -596,3 +626,13 @@ func (w *workflowUsecaseImpl) createTestApplicationRevision(ctx context.Context,
 	}
 	return nil
 }
+
+// fakeNotificationUsecase records the emitted events
+type fakeNotificationUsecase struct {
+	NotificationUsecase
+	events []string
+}
+
+func (f *fakeNotificationUsecase) Emit(ctx context.Context, event string, data map[string]string) {
+	f.events = append(f.events, event)
+}
@@ -39,4 +39,22 @@ var (
 	ErrRefreshTokenExpired = NewBcode(400, 12010, "the refresh token is expired")
 	// ErrNoDexConnector is the error of no dex connector
 	ErrNoDexConnector = NewBcode(400, 12011, "there is no dex connector")
+	// ErrOIDCConfigNotFound is the error of the oidc config is not configured
+	ErrOIDCConfigNotFound = NewBcode(400, 12012, "the oidc config is not found")
+	// ErrInvalidOIDCConfig is the error of invalid oidc config
+	ErrInvalidOIDCConfig = NewBcode(400, 12013, "the oidc config is invalid")
+	// ErrOIDCClaimMissing is the error of the required claim is missing in the id token
+	ErrOIDCClaimMissing = NewBcode(400, 12014, "the required claim is missing in the id token")
+	// ErrAPITokenNotExist is the error of the api token is not exist
+	ErrAPITokenNotExist = NewBcode(404, 12015, "the api token is not exist")
+	// ErrAPITokenExpired is the error of the api token is expired
+	ErrAPITokenExpired = NewBcode(401, 12016, "the api token is expired")
+	// ErrAPITokenScopeForbidden is the error of the request is out of the scope of the api token
+	ErrAPITokenScopeForbidden = NewBcode(403, 12017, "the request is out of the scope of the api token")
+	// ErrAPITokenInvalidProjects is the error of the project scoped token without valid projects
+	ErrAPITokenInvalidProjects = NewBcode(400, 12018, "the project scoped token must bind at least one existing project")
+	// ErrOIDCLoginFailed is the error of the code exchange or the id token verification with the oidc provider failed
+	ErrOIDCLoginFailed = NewBcode(401, 12019, "failed to login with the oidc provider")
+	// ErrOIDCEmailNotVerified is the error of the email in the id token is not verified by the oidc provider
+	ErrOIDCEmailNotVerified = NewBcode(401, 12020, "the email is not verified by the oidc provider")
 )
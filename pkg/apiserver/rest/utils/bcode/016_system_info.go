@@ -0,0 +1,28 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bcode
+
+var (
+	// ErrUnsupportedBundleVersion means the version of the system config bundle is not supported
+	ErrUnsupportedBundleVersion = NewBcode(400, 16001, "the version of the system config bundle is not supported")
+	// ErrInvalidBundle means the system config bundle can not be parsed
+	ErrInvalidBundle = NewBcode(400, 16002, "the system config bundle is invalid")
+	// ErrCollectionDisabled means the collection is disabled so there is nothing to export
+	ErrCollectionDisabled = NewBcode(400, 16003, "the collection is disabled")
+	// ErrSystemInitialized means the setup is refused because the system has been initialized
+	ErrSystemInitialized = NewBcode(400, 16004, "the system has been initialized")
+)
@@ -0,0 +1,26 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bcode
+
+var (
+	// ErrNotificationWebhookExist means the notification webhook name is already used
+	ErrNotificationWebhookExist = NewBcode(400, 17001, "the notification webhook name is already used")
+	// ErrNotificationWebhookNotExist means the notification webhook is not exist
+	ErrNotificationWebhookNotExist = NewBcode(404, 17002, "the notification webhook is not exist")
+	// ErrInvalidNotificationEvent means the subscribed event is not supported
+	ErrInvalidNotificationEvent = NewBcode(400, 17003, "the subscribed event is not supported")
+)
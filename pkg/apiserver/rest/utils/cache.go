@@ -18,6 +18,7 @@ package utils
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"time"
 )
@@ -92,6 +93,16 @@ func (m *MemoryCacheStore) Delete(key interface{}) {
 	m.store.Delete(key)
 }
 
+// DeleteByPrefix deletes the cache data whose key is a string starting with the prefix
+func (m *MemoryCacheStore) DeleteByPrefix(prefix string) {
+	m.store.Range(func(key, value interface{}) bool {
+		if k, ok := key.(string); ok && strings.HasPrefix(k, prefix) {
+			m.store.Delete(key)
+		}
+		return true
+	})
+}
+
 // Get cache data from store, if not exist or timeout, will return nil
 func (m *MemoryCacheStore) Get(key interface{}) (value interface{}) {
 	mc, ok := m.store.Load(key)
@@ -49,6 +49,17 @@ var _ = Describe("Test cache utils", func() {
 		store.Delete("test")
 		Expect(store.Get("test")).Should(BeNil())
 	})
+
+	It("test cache store delete keys by prefix", func() {
+		store := NewMemoryCacheStore(context.TODO())
+		store.Put("definition/component", "test data", time.Minute*2)
+		store.Put("definition/trait", "test data", time.Minute*2)
+		store.Put("addon/registries", "test data", time.Minute*2)
+		store.DeleteByPrefix("definition/")
+		Expect(store.Get("definition/component")).Should(BeNil())
+		Expect(store.Get("definition/trait")).Should(BeNil())
+		Expect(store.Get("addon/registries")).Should(Equal("test data"))
+	})
 })
 
 var store *MemoryCacheStore
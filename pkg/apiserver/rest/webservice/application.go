@@ -67,6 +67,9 @@ func (c *applicationWebService) GetWebService() *restful.WebService {
 		Param(ws.QueryParameter("project", "search base on project name").DataType("string")).
 		Param(ws.QueryParameter("env", "search base on env name").DataType("string")).
 		Param(ws.QueryParameter("targetName", "Name of the application delivery target").DataType("string")).
+		Param(ws.QueryParameter("page", "Page for paging, all applications are returned if not specified").DataType("integer")).
+		Param(ws.QueryParameter("pageSize", "PageSize for paging").DataType("integer")).
+		Param(ws.QueryParameter("sort", "Sort keys separated by commas, prefix with - for descending, supports name, alias, createTime and updateTime").DataType("string")).
 		// This api will filter the app by user's permissions
 		// Filter(c.rbacUsecase.CheckPerm("application", "list")).
 		Returns(200, "OK", apis.ListApplicationResponse{}).
@@ -622,17 +625,30 @@ func (c *applicationWebService) listApplications(req *restful.Request, res *rest
 	if req.QueryParameter("project") != "" {
 		projetNames = append(projetNames, req.QueryParameter("project"))
 	}
+	page, pageSize, err := utils.ExtractPagingParams(req, minPageSize, maxPageSize)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	sortBy, err := extractSortParams(req, "name", "alias", "createTime", "updateTime")
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
 	apps, err := c.applicationUsecase.ListApplications(req.Request.Context(), apis.ListApplicationOptions{
 		Projects:   projetNames,
 		Env:        req.QueryParameter("env"),
 		TargetName: req.QueryParameter("targetName"),
 		Query:      req.QueryParameter("query"),
+		Page:       page,
+		PageSize:   pageSize,
+		SortBy:     sortBy,
 	})
 	if err != nil {
 		bcode.ReturnError(req, res, err)
 		return
 	}
-	if err := res.WriteEntity(apis.ListApplicationResponse{Applications: apps}); err != nil {
+	if err := res.WriteEntity(apps); err != nil {
 		bcode.ReturnError(req, res, err)
 		return
 	}
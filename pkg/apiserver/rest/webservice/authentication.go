@@ -18,11 +18,13 @@ package webservice
 
 import (
 	"context"
+	"net/http"
 	"strings"
 
 	restfulspec "github.com/emicklei/go-restful-openapi/v2"
 	"github.com/emicklei/go-restful/v3"
 
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
 	apis "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
 	"github.com/oam-dev/kubevela/pkg/apiserver/rest/usecase"
 	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
@@ -31,13 +33,15 @@ import (
 type authenticationWebService struct {
 	authenticationUsecase usecase.AuthenticationUsecase
 	userUsecase           usecase.UserUsecase
+	tokenUsecase          usecase.TokenUsecase
 }
 
 // NewAuthenticationWebService is the webservice of authentication
-func NewAuthenticationWebService(authenticationUsecase usecase.AuthenticationUsecase, userUsecase usecase.UserUsecase) WebService {
+func NewAuthenticationWebService(authenticationUsecase usecase.AuthenticationUsecase, userUsecase usecase.UserUsecase, tokenUsecase usecase.TokenUsecase) WebService {
 	return &authenticationWebService{
 		authenticationUsecase: authenticationUsecase,
 		userUsecase:           userUsecase,
+		tokenUsecase:          tokenUsecase,
 	}
 }
 
@@ -65,6 +69,13 @@ func (c *authenticationWebService) GetWebService() *restful.WebService {
 		Returns(400, "", bcode.Bcode{}).
 		Writes(apis.DexConfigResponse{}))
 
+	ws.Route(ws.GET("/oidc_config").To(c.getOIDCConfig).
+		Doc("get the generic OIDC provider config").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Returns(200, "", apis.OIDCConfigBase{}).
+		Returns(400, "", bcode.Bcode{}).
+		Writes(apis.OIDCConfigBase{}))
+
 	ws.Route(ws.GET("/refresh_token").To(c.refreshToken).
 		Doc("refresh token").
 		Metadata(restfulspec.KeyOpenAPITags, tags).
@@ -86,9 +97,57 @@ func (c *authenticationWebService) GetWebService() *restful.WebService {
 		Returns(200, "", apis.LoginUserInfoResponse{}).
 		Returns(400, "", bcode.Bcode{}).
 		Writes(apis.LoginUserInfoResponse{}))
+
+	ws.Route(ws.GET("/tokens").To(c.listAPITokens).
+		Doc("list the api tokens of the login user").
+		Filter(authCheckFilter).
+		Filter(sessionOnlyFilter).
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Returns(200, "", apis.ListAPITokenResponse{}).
+		Returns(400, "", bcode.Bcode{}).
+		Writes(apis.ListAPITokenResponse{}))
+
+	ws.Route(ws.POST("/tokens").To(c.createAPIToken).
+		Doc("create an api token for the login user, the token is only returned once").
+		Filter(authCheckFilter).
+		Filter(sessionOnlyFilter).
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Reads(apis.CreateAPITokenRequest{}).
+		Returns(200, "", apis.CreateAPITokenResponse{}).
+		Returns(400, "", bcode.Bcode{}).
+		Writes(apis.CreateAPITokenResponse{}))
+
+	ws.Route(ws.DELETE("/tokens/{tokenID}").To(c.deleteAPIToken).
+		Doc("revoke an api token of the login user").
+		Filter(authCheckFilter).
+		Filter(sessionOnlyFilter).
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("tokenID", "identifier of the api token").DataType("string")).
+		Returns(200, "", apis.EmptyResponse{}).
+		Returns(400, "", bcode.Bcode{}).
+		Writes(apis.EmptyResponse{}))
 	return ws
 }
 
+// NewAPITokenFilter validates the api token carried by the request, the validated token is saved into the request context
+// and it is used by the authCheckFilter. The requests without api tokens are passed through.
+func NewAPITokenFilter(tokenUsecase usecase.TokenUsecase) restful.FilterFunction {
+	return func(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
+		splitted := strings.Split(req.HeaderParameter("Authorization"), " ")
+		if len(splitted) != 2 || !strings.HasPrefix(splitted[1], usecase.APITokenPrefix) {
+			chain.ProcessFilter(req, res)
+			return
+		}
+		apiToken, err := tokenUsecase.ValidateAPIToken(req.Request.Context(), splitted[1])
+		if err != nil {
+			bcode.ReturnError(req, res, err)
+			return
+		}
+		req.Request = req.Request.WithContext(context.WithValue(req.Request.Context(), &apis.CtxKeyAPIToken, apiToken))
+		chain.ProcessFilter(req, res)
+	}
+}
+
 func authCheckFilter(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
 	tokenHeader := req.HeaderParameter("Authorization")
 	if tokenHeader == "" {
@@ -101,6 +160,33 @@ func authCheckFilter(req *restful.Request, res *restful.Response, chain *restful
 		return
 	}
 
+	if strings.HasPrefix(splitted[1], usecase.APITokenPrefix) {
+		// the api token is validated by the filter created by NewAPITokenFilter
+		apiToken, ok := req.Request.Context().Value(&apis.CtxKeyAPIToken).(*model.APIToken)
+		if !ok {
+			bcode.ReturnError(req, res, bcode.ErrNotAuthorized)
+			return
+		}
+		if apiToken.Scope == model.APITokenScopeReadOnly && req.Request.Method != http.MethodGet {
+			bcode.ReturnError(req, res, bcode.ErrAPITokenScopeForbidden)
+			return
+		}
+		req.Request = req.Request.WithContext(context.WithValue(req.Request.Context(), &apis.CtxKeyUser, apiToken.Username))
+		if apiToken.Scope == model.APITokenScopeProject {
+			// the project scope is checked by the rbac filter, reject the routes without it
+			target := chain.Target
+			chain.Target = func(req *restful.Request, res *restful.Response) {
+				if checked, ok := req.Attribute(usecase.APITokenScopeCheckedAttribute).(bool); !ok || !checked {
+					bcode.ReturnError(req, res, bcode.ErrAPITokenScopeForbidden)
+					return
+				}
+				target(req, res)
+			}
+		}
+		chain.ProcessFilter(req, res)
+		return
+	}
+
 	token, err := usecase.ParseToken(splitted[1])
 	if err != nil {
 		bcode.ReturnError(req, res, err)
@@ -115,6 +201,16 @@ func authCheckFilter(req *restful.Request, res *restful.Response, chain *restful
 	chain.ProcessFilter(req, res)
 }
 
+// sessionOnlyFilter rejects the requests authenticated by the api tokens, it avoids a token to issue new tokens
+// or to export the secrets of the whole system even if it is read-only.
+func sessionOnlyFilter(req *restful.Request, res *restful.Response, chain *restful.FilterChain) {
+	if _, ok := req.Request.Context().Value(&apis.CtxKeyAPIToken).(*model.APIToken); ok {
+		bcode.ReturnError(req, res, bcode.ErrAPITokenScopeForbidden)
+		return
+	}
+	chain.ProcessFilter(req, res)
+}
+
 func (c *authenticationWebService) login(req *restful.Request, res *restful.Response) {
 	var loginReq apis.LoginRequest
 	if err := req.ReadEntity(&loginReq); err != nil {
@@ -144,6 +240,18 @@ func (c *authenticationWebService) getDexConfig(req *restful.Request, res *restf
 	}
 }
 
+func (c *authenticationWebService) getOIDCConfig(req *restful.Request, res *restful.Response) {
+	base, err := c.authenticationUsecase.GetOIDCConfig(req.Request.Context())
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(base); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
 func (c *authenticationWebService) refreshToken(req *restful.Request, res *restful.Response) {
 	base, err := c.authenticationUsecase.RefreshToken(req.Request.Context(), req.HeaderParameter("RefreshToken"))
 	if err != nil {
@@ -179,3 +287,47 @@ func (c *authenticationWebService) getLoginUserInfo(req *restful.Request, res *r
 		return
 	}
 }
+
+func (c *authenticationWebService) listAPITokens(req *restful.Request, res *restful.Response) {
+	tokens, err := c.tokenUsecase.ListAPITokens(req.Request.Context())
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(tokens); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (c *authenticationWebService) createAPIToken(req *restful.Request, res *restful.Response) {
+	var createReq apis.CreateAPITokenRequest
+	if err := req.ReadEntity(&createReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := validate.Struct(&createReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	token, err := c.tokenUsecase.CreateAPIToken(req.Request.Context(), createReq)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(token); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (c *authenticationWebService) deleteAPIToken(req *restful.Request, res *restful.Response) {
+	if err := c.tokenUsecase.DeleteAPIToken(req.Request.Context(), req.PathParameter("tokenID")); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(apis.EmptyResponse{}); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
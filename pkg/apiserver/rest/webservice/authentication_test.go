@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/oam-dev/kubevela/pkg/apiserver/datastore"
+	"github.com/oam-dev/kubevela/pkg/apiserver/model"
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/usecase"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
+)
+
+var _ = Describe("Test the api token scope", func() {
+	var (
+		container    *restful.Container
+		token        string
+		tokenUsecase usecase.TokenUsecase
+		rbacUsecase  usecase.RBACUsecase
+	)
+
+	BeforeEach(func() {
+		ds, err := NewDatastore(datastore.Config{Type: "kubeapi", Database: "token-scope-" + strconv.FormatInt(time.Now().UnixNano(), 10)})
+		Expect(err).Should(BeNil())
+		Expect(ds.Add(context.TODO(), &model.User{Name: "scope-user", Email: "scope@test.com"})).Should(Succeed())
+		Expect(ds.Add(context.TODO(), &model.Project{Name: "scope-project", Owner: "scope-user"})).Should(Succeed())
+
+		tokenUsecase = usecase.NewTokenUsecase(ds)
+		rbacUsecase = usecase.NewRBACUsecase(ds)
+		resp, err := tokenUsecase.CreateAPIToken(context.WithValue(context.TODO(), &apisv1.CtxKeyUser, "scope-user"), apisv1.CreateAPITokenRequest{
+			Name:     "scope-token",
+			Scope:    model.APITokenScopeProject,
+			Projects: []string{"scope-project"},
+		})
+		Expect(err).Should(BeNil())
+		token = resp.Token
+
+		ok := func(req *restful.Request, res *restful.Response) {
+			res.WriteHeader(http.StatusOK)
+		}
+		ws := new(restful.WebService)
+		ws.Route(ws.GET("/users").To(ok).
+			Filter(authCheckFilter).
+			Filter(rbacUsecase.CheckPerm("user", "list")))
+		ws.Route(ws.GET("/user_info").To(ok).
+			Filter(authCheckFilter))
+		container = restful.NewContainer()
+		container.Filter(NewAPITokenFilter(tokenUsecase))
+		container.Add(ws)
+	})
+
+	request := func(path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		container.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	It("Test the project scoped token can not escape the scope by the query parameter", func() {
+		Expect(request("/users?project=scope-project")).Should(Equal(http.StatusForbidden))
+		Expect(request("/users?project=x")).Should(Equal(http.StatusForbidden))
+	})
+
+	It("Test the project scoped token is rejected by the routes without the rbac filter", func() {
+		Expect(request("/user_info")).Should(Equal(http.StatusForbidden))
+	})
+
+	It("Test the read-only token can not export the system config", func() {
+		resp, err := tokenUsecase.CreateAPIToken(context.WithValue(context.TODO(), &apisv1.CtxKeyUser, "scope-user"), apisv1.CreateAPITokenRequest{
+			Name:  "read-only-token",
+			Scope: model.APITokenScopeReadOnly,
+		})
+		Expect(err).Should(BeNil())
+		token = resp.Token
+		container.Add(NewSystemInfoWebService(nil, nil, rbacUsecase).GetWebService())
+		for _, path := range []string{"/api/v1/system_info/export?includeSecrets=true", "/api/v1/system_info/collection-export"} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			container.ServeHTTP(rec, req)
+			Expect(rec.Code).Should(Equal(http.StatusForbidden))
+			var body bcode.Bcode
+			Expect(json.Unmarshal(rec.Body.Bytes(), &body)).Should(Succeed())
+			Expect(body.BusinessCode).Should(Equal(bcode.ErrAPITokenScopeForbidden.BusinessCode))
+		}
+	})
+})
@@ -50,6 +50,11 @@ func (d *definitionWebservice) GetWebService() *restful.WebService {
 		Param(ws.QueryParameter("type", "query the definition type").DataType("string").Required(true).AllowableValues(map[string]string{"component": "", "trait": "", "workflowstep": ""})).
 		Param(ws.QueryParameter("queryAll", "query all definitions include hidden in UI").DataType("boolean").DefaultValue("false")).
 		Param(ws.QueryParameter("appliedWorkload", "if specified, query the trait definition applied to the workload").DataType("string")).
+		Param(ws.QueryParameter("query", "Fuzzy search based on name, alias or description").DataType("string")).
+		Param(ws.QueryParameter("status", "query the definitions by status").DataType("string").AllowableValues(map[string]string{"enable": "", "disable": ""})).
+		Param(ws.QueryParameter("page", "Page for paging, all definitions are returned if not specified").DataType("integer")).
+		Param(ws.QueryParameter("pageSize", "PageSize for paging").DataType("integer")).
+		Param(ws.QueryParameter("sort", "Sort keys separated by commas, prefix with - for descending, supports name, alias and status").DataType("string")).
 		Returns(200, "OK", apis.ListDefinitionResponse{}).
 		Writes(apis.ListDefinitionResponse{}).Do(returns200, returns500))
 
@@ -95,16 +100,31 @@ func (d *definitionWebservice) listDefinitions(req *restful.Request, res *restfu
 	if err != nil {
 		queryAll = false
 	}
+	page, pageSize, err := utils.ExtractPagingParams(req, minPageSize, maxPageSize)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	sortBy, err := extractSortParams(req, "name", "alias", "status")
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
 	definitions, err := d.definitionUsecase.ListDefinitions(req.Request.Context(), usecase.DefinitionQueryOption{
 		Type:             req.QueryParameter("type"),
 		AppliedWorkloads: req.QueryParameter("appliedWorkload"),
 		QueryAll:         queryAll,
+		Query:            req.QueryParameter("query"),
+		Status:           req.QueryParameter("status"),
+		Page:             page,
+		PageSize:         pageSize,
+		SortBy:           sortBy,
 	})
 	if err != nil {
 		bcode.ReturnError(req, res, err)
 		return
 	}
-	if err := res.WriteEntity(apis.ListDefinitionResponse{Definitions: definitions}); err != nil {
+	if err := res.WriteEntity(definitions); err != nil {
 		bcode.ReturnError(req, res, err)
 		return
 	}
@@ -117,8 +117,8 @@ func (n *envWebService) delete(req *restful.Request, res *restful.Response) {
 		bcode.ReturnError(req, res, err)
 		return
 	}
-	if len(lists) > 0 {
-		log.Logger.Infof("detected %d applications in this env, the first is %s", len(lists), lists[0].Name)
+	if len(lists.Applications) > 0 {
+		log.Logger.Infof("detected %d applications in this env, the first is %s", len(lists.Applications), lists.Applications[0].Name)
 		bcode.ReturnError(req, res, bcode.ErrDeleteEnvButAppExist)
 		return
 	}
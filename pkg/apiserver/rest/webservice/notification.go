@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webservice
+
+import (
+	restfulspec "github.com/emicklei/go-restful-openapi/v2"
+	"github.com/emicklei/go-restful/v3"
+
+	apis "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/usecase"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
+)
+
+// NewNotificationWebService new notification webservice
+func NewNotificationWebService(notificationUsecase usecase.NotificationUsecase, rbacUsecase usecase.RBACUsecase) WebService {
+	return &notificationWebService{
+		notificationUsecase: notificationUsecase,
+		rbacUsecase:         rbacUsecase,
+	}
+}
+
+type notificationWebService struct {
+	notificationUsecase usecase.NotificationUsecase
+	rbacUsecase         usecase.RBACUsecase
+}
+
+func (n *notificationWebService) GetWebService() *restful.WebService {
+	ws := new(restful.WebService)
+	ws.Path(versionPrefix+"/notifications").
+		Consumes(restful.MIME_XML, restful.MIME_JSON).
+		Produces(restful.MIME_JSON, restful.MIME_XML).
+		Doc("api for notification manage")
+
+	tags := []string{"notification"}
+
+	ws.Route(ws.GET("/webhooks").To(n.listWebhooks).
+		Doc("list the notification webhooks").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Filter(n.rbacUsecase.CheckPerm("notification", "list")).
+		Returns(200, "OK", apis.ListNotificationWebhookResponse{}).
+		Writes(apis.ListNotificationWebhookResponse{}).Do(returns200, returns500))
+
+	ws.Route(ws.POST("/webhooks").To(n.createWebhook).
+		Doc("create a notification webhook").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Reads(apis.CreateNotificationWebhookRequest{}).
+		Filter(n.rbacUsecase.CheckPerm("notification", "create")).
+		Returns(200, "OK", apis.NotificationWebhookBase{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.NotificationWebhookBase{}).Do(returns200, returns500))
+
+	ws.Route(ws.PUT("/webhooks/{webhookName}").To(n.updateWebhook).
+		Doc("update a notification webhook").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("webhookName", "identifier of the notification webhook").DataType("string")).
+		Reads(apis.UpdateNotificationWebhookRequest{}).
+		Filter(n.rbacUsecase.CheckPerm("notification", "update")).
+		Returns(200, "OK", apis.NotificationWebhookBase{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.NotificationWebhookBase{}).Do(returns200, returns500))
+
+	ws.Route(ws.DELETE("/webhooks/{webhookName}").To(n.deleteWebhook).
+		Doc("delete a notification webhook").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("webhookName", "identifier of the notification webhook").DataType("string")).
+		Filter(n.rbacUsecase.CheckPerm("notification", "delete")).
+		Returns(200, "OK", apis.EmptyResponse{}).
+		Returns(404, "Not Found", bcode.Bcode{}).
+		Writes(apis.EmptyResponse{}).Do(returns200, returns500))
+
+	ws.Route(ws.GET("/webhooks/{webhookName}/deliveries").To(n.listDeliveries).
+		Doc("list the delivery history of a notification webhook").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.PathParameter("webhookName", "identifier of the notification webhook").DataType("string")).
+		Param(ws.QueryParameter("page", "Page for paging").DataType("integer")).
+		Param(ws.QueryParameter("pageSize", "PageSize for paging").DataType("integer")).
+		Filter(n.rbacUsecase.CheckPerm("notification", "detail")).
+		Returns(200, "OK", apis.ListNotificationDeliveryResponse{}).
+		Returns(404, "Not Found", bcode.Bcode{}).
+		Writes(apis.ListNotificationDeliveryResponse{}).Do(returns200, returns500))
+
+	ws.Route(ws.POST("/test").To(n.testWebhook).
+		Doc("send a test event to a notification webhook").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Reads(apis.TestNotificationRequest{}).
+		Filter(n.rbacUsecase.CheckPerm("notification", "update")).
+		Returns(200, "OK", apis.NotificationDeliveryBase{}).
+		Returns(404, "Not Found", bcode.Bcode{}).
+		Writes(apis.NotificationDeliveryBase{}).Do(returns200, returns500))
+
+	ws.Filter(authCheckFilter)
+	return ws
+}
+
+func (n *notificationWebService) listWebhooks(req *restful.Request, res *restful.Response) {
+	webhooks, err := n.notificationUsecase.ListWebhooks(req.Request.Context())
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(webhooks); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *notificationWebService) createWebhook(req *restful.Request, res *restful.Response) {
+	var createReq apis.CreateNotificationWebhookRequest
+	if err := req.ReadEntity(&createReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := validate.Struct(&createReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	webhook, err := n.notificationUsecase.CreateWebhook(req.Request.Context(), createReq)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(webhook); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *notificationWebService) updateWebhook(req *restful.Request, res *restful.Response) {
+	var updateReq apis.UpdateNotificationWebhookRequest
+	if err := req.ReadEntity(&updateReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := validate.Struct(&updateReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	webhook, err := n.notificationUsecase.UpdateWebhook(req.Request.Context(), req.PathParameter("webhookName"), updateReq)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(webhook); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *notificationWebService) deleteWebhook(req *restful.Request, res *restful.Response) {
+	if err := n.notificationUsecase.DeleteWebhook(req.Request.Context(), req.PathParameter("webhookName")); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(apis.EmptyResponse{}); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *notificationWebService) listDeliveries(req *restful.Request, res *restful.Response) {
+	page, pageSize, err := utils.ExtractPagingParams(req, minPageSize, maxPageSize)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	deliveries, err := n.notificationUsecase.ListDeliveries(req.Request.Context(), req.PathParameter("webhookName"), page, pageSize)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(deliveries); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (n *notificationWebService) testWebhook(req *restful.Request, res *restful.Response) {
+	var testReq apis.TestNotificationRequest
+	if err := req.ReadEntity(&testReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := validate.Struct(&testReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	delivery, err := n.notificationUsecase.TestWebhook(req.Request.Context(), testReq.Webhook)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(delivery); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
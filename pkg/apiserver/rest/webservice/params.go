@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webservice
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/emicklei/go-restful/v3"
+
+	apis "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils"
+)
+
+// extractSortParams extract the `sort` param from request, multiple keys are separated by commas
+// and the key prefixed with `-` is sorted in descending order, e.g. `sort=-createTime,name`.
+// Only the allowed keys could be used.
+func extractSortParams(req *restful.Request, allowedKeys ...string) ([]apis.SortOption, error) {
+	sortStr := req.QueryParameter("sort")
+	if sortStr == "" {
+		return nil, nil
+	}
+	var sortBy []apis.SortOption
+	for _, key := range strings.Split(sortStr, ",") {
+		key = strings.TrimSpace(key)
+		descending := strings.HasPrefix(key, "-")
+		key = strings.TrimPrefix(key, "-")
+		if !utils.StringsContain(allowedKeys, key) {
+			return nil, restful.NewError(http.StatusBadRequest, fmt.Sprintf("invalid sort key %s, the allowed keys are %s", key, strings.Join(allowedKeys, ",")))
+		}
+		sortBy = append(sortBy, apis.SortOption{Key: key, Descending: descending})
+	}
+	return sortBy, nil
+}
+
+// extractFilterParams extract the params named as the allowed keys from request as the exact match filters
+func extractFilterParams(req *restful.Request, allowedKeys ...string) []apis.EqualFilter {
+	var filters []apis.EqualFilter
+	for _, key := range allowedKeys {
+		if value := req.QueryParameter(key); value != "" {
+			filters = append(filters, apis.EqualFilter{Key: key, Value: value})
+		}
+	}
+	return filters
+}
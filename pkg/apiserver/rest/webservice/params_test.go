@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webservice
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	apisv1 "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
+)
+
+var _ = Describe("Test the list params", func() {
+	It("Test extractSortParams function", func() {
+		req, err := http.NewRequest("GET", "/xx?sort=-createTime,name", nil)
+		Expect(err).Should(BeNil())
+		sortBy, err := extractSortParams(restful.NewRequest(req), "name", "createTime")
+		Expect(err).Should(BeNil())
+		Expect(cmp.Diff(sortBy, []apisv1.SortOption{
+			{Key: "createTime", Descending: true},
+			{Key: "name"},
+		})).Should(BeEmpty())
+
+		_, err = extractSortParams(restful.NewRequest(req), "name")
+		Expect(err).ShouldNot(BeNil())
+		var serviceErr restful.ServiceError
+		Expect(errors.As(err, &serviceErr)).Should(BeTrue())
+		Expect(serviceErr.Code).Should(Equal(http.StatusBadRequest))
+	})
+
+	It("Test extractFilterParams function", func() {
+		req, err := http.NewRequest("GET", "/xx?status=Healthy&password=x&alias=", nil)
+		Expect(err).Should(BeNil())
+		filters := extractFilterParams(restful.NewRequest(req), "status", "alias")
+		Expect(cmp.Diff(filters, []apisv1.EqualFilter{{Key: "status", Value: "Healthy"}})).Should(BeEmpty())
+	})
+})
@@ -291,7 +291,7 @@ func (n *projectWebService) listProjectTargets(req *restful.Request, res *restfu
 		bcode.ReturnError(req, res, err)
 		return
 	}
-	projects, err := n.targetUsecase.ListTargets(req.Request.Context(), 0, 0, project.Name)
+	projects, err := n.targetUsecase.ListTargets(req.Request.Context(), 0, 0, apis.ListTargetOptions{Project: project.Name})
 	if err != nil {
 		bcode.ReturnError(req, res, err)
 		return
@@ -17,22 +17,30 @@ limitations under the License.
 package webservice
 
 import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
 	restfulspec "github.com/emicklei/go-restful-openapi/v2"
 	"github.com/emicklei/go-restful/v3"
+	"sigs.k8s.io/yaml"
 
 	apis "github.com/oam-dev/kubevela/pkg/apiserver/rest/apis/v1"
 	"github.com/oam-dev/kubevela/pkg/apiserver/rest/usecase"
 	"github.com/oam-dev/kubevela/pkg/apiserver/rest/utils/bcode"
 )
 
+const mimeYAML = "application/x-yaml"
+
 type systemInfoWebService struct {
-	useCase     usecase.SystemInfoUsecase
-	rbacUsecase usecase.RBACUsecase
+	useCase      usecase.SystemInfoUsecase
+	setupUsecase usecase.SetupUsecase
+	rbacUsecase  usecase.RBACUsecase
 }
 
 // NewSystemInfoWebService return systemInfo webservice
-func NewSystemInfoWebService(systemInfoUseCase usecase.SystemInfoUsecase, rbacUsecase usecase.RBACUsecase) WebService {
-	return &systemInfoWebService{useCase: systemInfoUseCase, rbacUsecase: rbacUsecase}
+func NewSystemInfoWebService(systemInfoUseCase usecase.SystemInfoUsecase, setupUsecase usecase.SetupUsecase, rbacUsecase usecase.RBACUsecase) WebService {
+	return &systemInfoWebService{useCase: systemInfoUseCase, setupUsecase: setupUsecase, rbacUsecase: rbacUsecase}
 }
 
 // GetWebService return systemInfo webservice
@@ -47,6 +55,7 @@ func (u systemInfoWebService) GetWebService() *restful.WebService {
 	// Get
 	ws.Route(ws.GET("/").To(u.getSystemInfo).
 		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Filter(authCheckFilter).
 		Returns(200, "OK", apis.SystemInfoResponse{}).
 		Returns(400, "Bad Request", bcode.Bcode{}).
 		Writes(apis.SystemInfoResponse{}))
@@ -55,12 +64,69 @@ func (u systemInfoWebService) GetWebService() *restful.WebService {
 	ws.Route(ws.PUT("/").To(u.updateSystemInfo).
 		Metadata(restfulspec.KeyOpenAPITags, tags).
 		Reads(apis.SystemInfoRequest{}).
+		Filter(authCheckFilter).
 		Filter(u.rbacUsecase.CheckPerm("systemSetting", "update")).
 		Returns(200, "OK", apis.SystemInfoResponse{}).
 		Returns(400, "Bad Request", bcode.Bcode{}).
 		Writes(apis.SystemInfoResponse{}))
 
-	ws.Filter(authCheckFilter)
+	ws.Route(ws.GET("/collection-preview").To(u.previewCollection).
+		Doc("preview exactly the payload that would be reported by the collection").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Filter(authCheckFilter).
+		Filter(u.rbacUsecase.CheckPerm("systemSetting", "update")).
+		Returns(200, "OK", apis.CollectionPreviewResponse{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.CollectionPreviewResponse{}))
+
+	ws.Route(ws.GET("/collection-export").To(u.exportCollection).
+		Doc("download the collection payload as a file, it is used by the air-gapped clusters in the export only mode").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Filter(authCheckFilter).
+		Filter(sessionOnlyFilter).
+		Filter(u.rbacUsecase.CheckPerm("systemSetting", "update")).
+		Returns(200, "OK", apis.CollectionPayload{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.CollectionPayload{}))
+
+	ws.Route(ws.GET("/export").To(u.exportSystemConfig).
+		Doc("export all state managed by the apiserver as a bundle").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Param(ws.QueryParameter("includeSecrets", "whether to include the passwords, client secrets and integration properties").DataType("boolean")).
+		Param(ws.QueryParameter("format", "the format of the bundle, json or yaml, default is json").DataType("string")).
+		Filter(authCheckFilter).
+		Filter(sessionOnlyFilter).
+		Filter(u.rbacUsecase.CheckPerm("systemSetting", "update")).
+		Returns(200, "OK", apis.SystemConfigBundle{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.SystemConfigBundle{}))
+
+	ws.Route(ws.POST("/import").To(u.importSystemConfig).
+		Doc("import the bundle exported by another apiserver, the body could be json or yaml").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Consumes(restful.MIME_JSON, mimeYAML).
+		Reads(apis.SystemConfigBundle{}).
+		Filter(authCheckFilter).
+		Filter(u.rbacUsecase.CheckPerm("systemSetting", "update")).
+		Returns(200, "OK", apis.ImportSystemConfigResponse{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.ImportSystemConfigResponse{}))
+
+	// the setup routes are called before any user could login, so they are not authenticated
+	ws.Route(ws.GET("/setup-status").To(u.getSetupStatus).
+		Doc("get whether the system has been initialized").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Returns(200, "OK", apis.SystemSetupStatusResponse{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.SystemSetupStatusResponse{}))
+
+	ws.Route(ws.POST("/setup").To(u.setup).
+		Doc("set up a new installation, it is refused once the system has been initialized").
+		Metadata(restfulspec.KeyOpenAPITags, tags).
+		Reads(apis.SystemSetupRequest{}).
+		Returns(200, "OK", apis.SystemSetupStatusResponse{}).
+		Returns(400, "Bad Request", bcode.Bcode{}).
+		Writes(apis.SystemSetupStatusResponse{}))
 	return ws
 }
 
@@ -102,3 +168,113 @@ func (u systemInfoWebService) updateSystemInfo(req *restful.Request, res *restfu
 		return
 	}
 }
+
+func (u systemInfoWebService) previewCollection(req *restful.Request, res *restful.Response) {
+	preview, err := u.useCase.GetCollectionPreview(req.Request.Context())
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(preview); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (u systemInfoWebService) exportCollection(req *restful.Request, res *restful.Response) {
+	preview, err := u.useCase.GetCollectionPreview(req.Request.Context())
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if !preview.EnableCollection {
+		bcode.ReturnError(req, res, bcode.ErrCollectionDisabled)
+		return
+	}
+	res.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=kubevela-collection-%s.json", preview.Payload.PlatformID))
+	if err := res.WriteAsJson(preview.Payload); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (u systemInfoWebService) exportSystemConfig(req *restful.Request, res *restful.Response) {
+	includeSecrets, _ := strconv.ParseBool(req.QueryParameter("includeSecrets"))
+	bundle, err := u.useCase.ExportSystemConfig(req.Request.Context(), includeSecrets)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if req.QueryParameter("format") == "yaml" {
+		content, err := yaml.Marshal(bundle)
+		if err != nil {
+			bcode.ReturnError(req, res, err)
+			return
+		}
+		res.Header().Set(restful.HEADER_ContentType, mimeYAML)
+		if _, err := res.Write(content); err != nil {
+			bcode.ReturnError(req, res, err)
+		}
+		return
+	}
+	if err := res.WriteEntity(bundle); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (u systemInfoWebService) importSystemConfig(req *restful.Request, res *restful.Response) {
+	body, err := ioutil.ReadAll(req.Request.Body)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	// the json is also valid yaml, so both formats could be parsed here
+	var bundle apis.SystemConfigBundle
+	if err := yaml.Unmarshal(body, &bundle); err != nil {
+		bcode.ReturnError(req, res, bcode.ErrInvalidBundle)
+		return
+	}
+	resp, err := u.useCase.ImportSystemConfig(req.Request.Context(), bundle)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(resp); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (u systemInfoWebService) getSetupStatus(req *restful.Request, res *restful.Response) {
+	status, err := u.setupUsecase.GetSetupStatus(req.Request.Context())
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(status); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
+
+func (u systemInfoWebService) setup(req *restful.Request, res *restful.Response) {
+	var setupReq apis.SystemSetupRequest
+	if err := req.ReadEntity(&setupReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := validate.Struct(&setupReq); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	status, err := u.setupUsecase.Setup(req.Request.Context(), setupReq)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	if err := res.WriteEntity(status); err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+}
@@ -67,6 +67,10 @@ func (dt *TargetWebService) GetWebService() *restful.WebService {
 		Param(ws.QueryParameter("page", "Page for paging").DataType("integer")).
 		Param(ws.QueryParameter("pageSize", "PageSize for paging").DataType("integer")).
 		Param(ws.QueryParameter("project", "list targets by project name").DataType("string")).
+		Param(ws.QueryParameter("query", "Fuzzy search based on name, alias or description").DataType("string")).
+		Param(ws.QueryParameter("cluster.clusterName", "list targets by cluster name").DataType("string")).
+		Param(ws.QueryParameter("cluster.namespace", "list targets by namespace").DataType("string")).
+		Param(ws.QueryParameter("sort", "Sort keys separated by commas, prefix with - for descending, supports name, alias, createTime and updateTime").DataType("string")).
 		Returns(200, "OK", apis.ListTargetResponse{}).
 		Writes(apis.ListTargetResponse{}).Do(returns200, returns500))
 
@@ -192,7 +196,7 @@ func (dt *TargetWebService) deleteTarget(req *restful.Request, res *restful.Resp
 			return
 		}
 	}
-	if applications != nil {
+	if applications != nil && len(applications.Applications) > 0 {
 		bcode.ReturnError(req, res, bcode.ErrTargetInUseCantDeleted)
 		return
 	}
@@ -212,7 +216,17 @@ func (dt *TargetWebService) listTargets(req *restful.Request, res *restful.Respo
 		bcode.ReturnError(req, res, err)
 		return
 	}
-	Targets, err := dt.TargetUsecase.ListTargets(req.Request.Context(), page, pageSize, req.QueryParameter("project"))
+	sortBy, err := extractSortParams(req, "name", "alias", "createTime", "updateTime")
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
+	Targets, err := dt.TargetUsecase.ListTargets(req.Request.Context(), page, pageSize, apis.ListTargetOptions{
+		Project: req.QueryParameter("project"),
+		Query:   req.QueryParameter("query"),
+		Filters: extractFilterParams(req, "cluster.clusterName", "cluster.namespace"),
+		SortBy:  sortBy,
+	})
 	if err != nil {
 		bcode.ReturnError(req, res, err)
 		return
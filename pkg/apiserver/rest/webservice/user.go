@@ -60,6 +60,9 @@ func (c *userWebService) GetWebService() *restful.WebService {
 		Param(ws.QueryParameter("name", "fuzzy search based on name").DataType("string")).
 		Param(ws.QueryParameter("email", "fuzzy search based on email").DataType("string")).
 		Param(ws.QueryParameter("alias", "fuzzy search based on alias").DataType("string")).
+		Param(ws.QueryParameter("query", "fuzzy search based on name, alias or email").DataType("string")).
+		Param(ws.QueryParameter("disabled", "list the disabled or enabled users").DataType("boolean")).
+		Param(ws.QueryParameter("sort", "sort keys separated by commas, prefix with - for descending, supports name, alias, email, createTime and lastLoginTime").DataType("string")).
 		Returns(200, "OK", apis.ListUserResponse{}).
 		Returns(400, "Bad Request", bcode.Bcode{}).
 		Writes(apis.ListUserResponse{}))
@@ -183,10 +186,18 @@ func (c *userWebService) listUser(req *restful.Request, res *restful.Response) {
 		bcode.ReturnError(req, res, err)
 		return
 	}
+	sortBy, err := extractSortParams(req, "name", "alias", "email", "createTime", "lastLoginTime")
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
 	resp, err := c.userUsecase.ListUsers(req.Request.Context(), page, pageSize, apis.ListUserOptions{
-		Name:  req.QueryParameter("name"),
-		Alias: req.QueryParameter("alias"),
-		Email: req.QueryParameter("email"),
+		Name:    req.QueryParameter("name"),
+		Alias:   req.QueryParameter("alias"),
+		Email:   req.QueryParameter("email"),
+		Query:   req.QueryParameter("query"),
+		Filters: extractFilterParams(req, "disabled"),
+		SortBy:  sortBy,
 	})
 	if err != nil {
 		bcode.ReturnError(req, res, err)
@@ -59,24 +59,28 @@ func returns500(b *restful.RouteBuilder) {
 
 // Init inits all webservice, pass in the required parameter object.
 // It can be implemented using the idea of dependency injection.
-func Init(ctx context.Context, ds datastore.DataStore, addonCacheTime time.Duration, initDatabase bool) map[string]interface{} {
+func Init(ctx context.Context, ds datastore.DataStore, addonCacheTime time.Duration, disableCache, initDatabase bool) map[string]interface{} {
+	cache := usecase.NewCache(ctx, !disableCache)
+	notificationUsecase := usecase.NewNotificationUsecase(ctx, ds)
 	clusterUsecase := usecase.NewClusterUsecase(ds)
 	rbacUsecase := usecase.NewRBACUsecase(ds)
 	projectUsecase := usecase.NewProjectUsecase(ds, rbacUsecase)
 	envUsecase := usecase.NewEnvUsecase(ds, projectUsecase)
 	targetUsecase := usecase.NewTargetUsecase(ds)
-	workflowUsecase := usecase.NewWorkflowUsecase(ds, envUsecase)
+	workflowUsecase := usecase.NewWorkflowUsecase(ds, envUsecase, notificationUsecase)
 	oamApplicationUsecase := usecase.NewOAMApplicationUsecase()
 	velaQLUsecase := usecase.NewVelaQLUsecase()
-	definitionUsecase := usecase.NewDefinitionUsecase()
-	addonUsecase := usecase.NewAddonUsecase(addonCacheTime)
-	envBindingUsecase := usecase.NewEnvBindingUsecase(ds, workflowUsecase, definitionUsecase, envUsecase)
-	systemInfoUsecase := usecase.NewSystemInfoUsecase(ds)
+	definitionUsecase := usecase.NewDefinitionUsecase(cache)
+	addonUsecase := usecase.NewAddonUsecase(addonCacheTime, cache)
+	envBindingUsecase := usecase.NewEnvBindingUsecase(ds, workflowUsecase, definitionUsecase, envUsecase, notificationUsecase)
+	systemInfoUsecase := usecase.NewSystemInfoUsecase(ds, notificationUsecase, cache)
+	setupUsecase := usecase.NewSetupUsecase(ds, systemInfoUsecase, notificationUsecase, cache)
 	helmUsecase := usecase.NewHelmUsecase()
-	userUsecase := usecase.NewUserUsecase(ds, projectUsecase, systemInfoUsecase, rbacUsecase)
-	authenticationUsecase := usecase.NewAuthenticationUsecase(ds, systemInfoUsecase, userUsecase)
+	userUsecase := usecase.NewUserUsecase(ds, projectUsecase, systemInfoUsecase, rbacUsecase, notificationUsecase)
+	authenticationUsecase := usecase.NewAuthenticationUsecase(ds, systemInfoUsecase, userUsecase, notificationUsecase)
+	tokenUsecase := usecase.NewTokenUsecase(ds)
 	configUseCase := usecase.NewConfigUseCase(authenticationUsecase)
-	applicationUsecase := usecase.NewApplicationUsecase(ds, workflowUsecase, envBindingUsecase, envUsecase, targetUsecase, definitionUsecase, projectUsecase, userUsecase)
+	applicationUsecase := usecase.NewApplicationUsecase(ds, workflowUsecase, envBindingUsecase, envUsecase, targetUsecase, definitionUsecase, projectUsecase, userUsecase, notificationUsecase)
 	webhookUsecase := usecase.NewWebhookUsecase(ds, applicationUsecase)
 	// Modules that require default data initialization, Call it here in order
 	if initDatabase {
@@ -107,15 +111,18 @@ func Init(ctx context.Context, ds datastore.DataStore, addonCacheTime time.Durat
 	RegisterWebService(NewHelmWebService(helmUsecase))
 
 	// Authentication
-	RegisterWebService(NewAuthenticationWebService(authenticationUsecase, userUsecase))
+	RegisterWebService(NewAuthenticationWebService(authenticationUsecase, userUsecase, tokenUsecase))
 	RegisterWebService(NewUserWebService(userUsecase, rbacUsecase))
-	RegisterWebService(NewSystemInfoWebService(systemInfoUsecase, rbacUsecase))
+	RegisterWebService(NewSystemInfoWebService(systemInfoUsecase, setupUsecase, rbacUsecase))
+
+	// Notification
+	RegisterWebService(NewNotificationWebService(notificationUsecase, rbacUsecase))
 
 	// RBAC
 	RegisterWebService(NewRBACWebService(rbacUsecase))
 
 	// return some usecase instance
-	return map[string]interface{}{"workflow": workflowUsecase, "project": projectUsecase}
+	return map[string]interface{}{"workflow": workflowUsecase, "project": projectUsecase, "token": tokenUsecase}
 }
 
 // InitUsecase the usecase set that needs init data